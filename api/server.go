@@ -0,0 +1,431 @@
+package api
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+)
+
+// Options configures the API server
+type Options struct {
+	// Validate runs the validation pipeline over scanned dependencies
+	Validate func(deps scanner.AllDeps, versions scanner.AllVersions) ([]validator.ValidationResult, error)
+	// ParseSpec reads one "ecosystem:name[@version]" package spec
+	ParseSpec func(spec string) (ecosystem, name, version string, err error)
+	// Ready reports whether the server can take scans, e.g. once the
+	// advisory feeds have loaded
+	Ready func() bool
+	// CacheStats reports the shared registry cache's hits and misses
+	CacheStats func() (hits, misses int64)
+
+	MaxBodyBytes int64
+}
+
+// Report is the JSON answer to a scan or check
+type Report struct {
+	OK      bool                         `json:"ok"`
+	Counts  map[string]int               `json:"counts"`
+	Results []validator.ValidationResult `json:"results"`
+}
+
+// Server is the HTTP API:
+//
+//	POST /v1/scan    a .tar/.tar.gz of a project, or multipart manifest files
+//	POST /v1/check   {"packages": ["npm:left-pad@1.3.0", ...]}
+//	GET  /healthz    liveness
+//	GET  /readyz     readiness
+//	GET  /metrics    Prometheus metrics
+type Server struct {
+	opts    Options
+	mux     *http.ServeMux
+	metrics *metrics
+}
+
+// New returns a Server, defaulting the body limit to 10MB
+func New(opts Options) *Server {
+	if opts.MaxBodyBytes == 0 {
+		opts.MaxBodyBytes = 10 << 20
+	}
+
+	s := &Server{opts: opts, mux: http.NewServeMux(), metrics: newMetrics()}
+	s.mux.HandleFunc("POST /v1/scan", s.instrument("scan", s.handleScan))
+	s.mux.HandleFunc("POST /v1/check", s.instrument("check", s.handleCheck))
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	s.mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.ready() {
+			http.Error(w, "loading", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ready")
+	})
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) ready() bool {
+	return s.opts.Ready == nil || s.opts.Ready()
+}
+
+// handleScan accepts a project tarball or multipart manifest uploads. Add
+// ?lockfiles=true to read lockfiles and ?vendor=true for vendored code.
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) (int, error) {
+	if !s.ready() {
+		return http.StatusServiceUnavailable, errors.New("still loading advisory feeds")
+	}
+
+	query := r.URL.Query()
+	lockfiles := query.Get("lockfiles") == "true"
+	vendor := query.Get("vendor") == "true"
+
+	deps := make(scanner.AllDeps)
+	versions := make(scanner.AllVersions)
+	add := func(name string, data io.Reader) error {
+		name = path.Clean(strings.TrimPrefix(name, "./"))
+		if !scanner.IsManifest(name) || (!lockfiles && scanner.IsLockfile(name)) || (!vendor && scanner.InVendorDir(name)) {
+			return nil
+		}
+		content, err := io.ReadAll(data)
+		if err != nil {
+			return err
+		}
+		// the CLI skips manifests it can't parse (yarn.lock and
+		// pnpm-lock.yaml among them), and so does the API
+		scanner.MergeManifest(deps, versions, name, content)
+		return nil
+	}
+
+	body := http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var err error
+	switch mediaType {
+	case "multipart/form-data":
+		err = readMultipart(multipart.NewReader(body, params["boundary"]), add)
+	case "application/gzip", "application/x-gzip", "application/x-tar", "application/octet-stream", "":
+		err = readTarball(body, s.opts.MaxBodyBytes, add)
+	default:
+		return http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type %q", mediaType)
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || errors.Is(err, errTooLarge) {
+			return http.StatusRequestEntityTooLarge, fmt.Errorf("request larger than %d bytes", s.opts.MaxBodyBytes)
+		}
+		return http.StatusBadRequest, err
+	}
+
+	return s.validate(w, deps, versions)
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) (int, error) {
+	if !s.ready() {
+		return http.StatusServiceUnavailable, errors.New("still loading advisory feeds")
+	}
+
+	var req struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return http.StatusRequestEntityTooLarge, fmt.Errorf("request larger than %d bytes", s.opts.MaxBodyBytes)
+		}
+		return http.StatusBadRequest, err
+	}
+	if len(req.Packages) == 0 {
+		return http.StatusBadRequest, errors.New("no packages to check")
+	}
+
+	deps := make(scanner.AllDeps)
+	versions := make(scanner.AllVersions)
+	for _, spec := range req.Packages {
+		eco, name, version, err := s.opts.ParseSpec(spec)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+		if deps[eco] == nil {
+			deps[eco] = make(scanner.DepMap)
+			versions[eco] = make(scanner.DepMap)
+		}
+		deps[eco][name] = append(deps[eco][name], "request")
+		if version != "" {
+			versions[eco][name] = append(versions[eco][name], version)
+		}
+	}
+
+	return s.validate(w, deps, versions)
+}
+
+func (s *Server) validate(w http.ResponseWriter, deps scanner.AllDeps, versions scanner.AllVersions) (int, error) {
+	results, err := s.opts.Validate(deps, versions)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Source != results[j].Source {
+			return results[i].Source < results[j].Source
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	report := Report{OK: true, Counts: map[string]int{}, Results: results}
+	for _, r := range results {
+		report.Counts[r.Status]++
+		if r.Status != "safe" {
+			report.OK = false
+		}
+		s.metrics.packages.inc(r.Source, r.Status)
+	}
+	if report.Results == nil {
+		report.Results = []validator.ValidationResult{}
+	}
+
+	writeJSON(w, http.StatusOK, report)
+	return http.StatusOK, nil
+}
+
+var errTooLarge = errors.New("archive too large")
+
+// readTarball walks a tar archive, gzipped or not, handing manifests to
+// add. What add reads counts against the same limit as the upload so a
+// small archive can't expand without bound; files it skips don't.
+func readTarball(r io.Reader, limit int64, add func(string, io.Reader) error) error {
+	buffered := newPeekReader(r)
+	var archive io.Reader = buffered
+	if buffered.gzipped() {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		archive = gz
+	}
+
+	tr := tar.NewReader(archive)
+	budget := &limitedReader{remaining: limit}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		budget.r = tr
+		if err := add(hdr.Name, budget); err != nil {
+			return err
+		}
+	}
+}
+
+// limitedReader fails with errTooLarge once more than remaining bytes have
+// been read through it
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	n, err := l.r.Read(b)
+	if l.remaining -= int64(n); l.remaining < 0 {
+		return n, errTooLarge
+	}
+	return n, err
+}
+
+func readMultipart(mr *multipart.Reader, add func(string, io.Reader) error) error {
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// the file name is what picks the parser, and may include a path
+		name := part.FileName()
+		if name == "" {
+			name = part.FormName()
+		}
+		err = add(name, part)
+		part.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// peekReader lets readTarball sniff the gzip magic without consuming it
+type peekReader struct {
+	head []byte
+	r    io.Reader
+}
+
+func newPeekReader(r io.Reader) *peekReader {
+	head := make([]byte, 2)
+	n, _ := io.ReadFull(r, head)
+	return &peekReader{head: head[:n], r: r}
+}
+
+func (p *peekReader) gzipped() bool {
+	return len(p.head) == 2 && p.head[0] == 0x1f && p.head[1] == 0x8b
+}
+
+func (p *peekReader) Read(b []byte) (int, error) {
+	if len(p.head) > 0 {
+		n := copy(b, p.head)
+		p.head = p.head[n:]
+		return n, nil
+	}
+	return p.r.Read(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// instrument times a handler and records its outcome; handlers return
+// their status, and errors are written as {"error": "..."}
+func (s *Server) instrument(endpoint string, handler func(http.ResponseWriter, *http.Request) (int, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status, err := handler(w, r)
+		if err != nil {
+			writeJSON(w, status, map[string]string{"error": err.Error()})
+		}
+		s.metrics.requests.inc(endpoint, strconv.Itoa(status))
+		s.metrics.observe(endpoint, time.Since(start))
+	}
+}
+
+// metrics holds the counters served at /metrics
+type metrics struct {
+	requests *counterVec
+	packages *counterVec
+
+	mu       sync.Mutex
+	duration map[string]*summary
+}
+
+type summary struct {
+	sum   float64
+	count int64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: &counterVec{labels: []string{"endpoint", "code"}, values: map[string]*atomic.Int64{}},
+		packages: &counterVec{labels: []string{"ecosystem", "status"}, values: map[string]*atomic.Int64{}},
+		duration: map[string]*summary{},
+	}
+}
+
+func (m *metrics) observe(endpoint string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.duration[endpoint] == nil {
+		m.duration[endpoint] = &summary{}
+	}
+	m.duration[endpoint].sum += d.Seconds()
+	m.duration[endpoint].count++
+}
+
+// counterVec is a counter with labels, keyed by its joined label values
+type counterVec struct {
+	labels []string
+	mu     sync.Mutex
+	values map[string]*atomic.Int64
+}
+
+func (c *counterVec) inc(values ...string) {
+	key := strings.Join(values, "\x00")
+	c.mu.Lock()
+	counter, found := c.values[key]
+	if !found {
+		counter = &atomic.Int64{}
+		c.values[key] = counter
+	}
+	c.mu.Unlock()
+	counter.Add(1)
+}
+
+func (c *counterVec) write(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var pairs []string
+		for i, v := range strings.Split(key, "\x00") {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", c.labels[i], v))
+		}
+		fmt.Fprintf(w, "%s{%s} %d\n", name, strings.Join(pairs, ","), c.values[key].Load())
+	}
+}
+
+// handleMetrics writes the Prometheus text exposition format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	s.metrics.requests.write(w, "vibe_validator_requests_total", "API requests by endpoint and status code.")
+	s.metrics.packages.write(w, "vibe_validator_packages_total", "Packages validated by ecosystem and status.")
+
+	fmt.Fprintf(w, "# HELP vibe_validator_request_duration_seconds Time spent handling API requests.\n# TYPE vibe_validator_request_duration_seconds summary\n")
+	s.metrics.mu.Lock()
+	endpoints := make([]string, 0, len(s.metrics.duration))
+	for endpoint := range s.metrics.duration {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		d := s.metrics.duration[endpoint]
+		fmt.Fprintf(w, "vibe_validator_request_duration_seconds_sum{endpoint=%q} %g\n", endpoint, d.sum)
+		fmt.Fprintf(w, "vibe_validator_request_duration_seconds_count{endpoint=%q} %d\n", endpoint, d.count)
+	}
+	s.metrics.mu.Unlock()
+
+	if s.opts.CacheStats != nil {
+		hits, misses := s.opts.CacheStats()
+		fmt.Fprintf(w, "# HELP vibe_validator_cache_hits_total Registry lookups answered from the shared cache.\n# TYPE vibe_validator_cache_hits_total counter\nvibe_validator_cache_hits_total %d\n", hits)
+		fmt.Fprintf(w, "# HELP vibe_validator_cache_misses_total Registry lookups that went to a registry.\n# TYPE vibe_validator_cache_misses_total counter\nvibe_validator_cache_misses_total %d\n", misses)
+	}
+
+	ready := 0
+	if s.ready() {
+		ready = 1
+	}
+	fmt.Fprintf(w, "# HELP vibe_validator_ready Whether the server is ready to take scans.\n# TYPE vibe_validator_ready gauge\nvibe_validator_ready %d\n", ready)
+}
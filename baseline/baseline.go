@@ -0,0 +1,168 @@
+package baseline
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+)
+
+// FileName is the conventional baseline file, committed at the project root
+const FileName = ".vibe-validator-baseline.json"
+
+// Entry is one accepted finding. Only stable fields go into the
+// fingerprint, so details that drift (ages, download counts) don't
+// invalidate it. Vulnerabilities are accepted one advisory at a time.
+type Entry struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`                // a finding type, or the status of a result that isn't safe
+	Reference string `json:"reference,omitempty"` // the advisory ID, for vulnerabilities
+}
+
+// Baseline is the set of findings a project has accepted
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// Create snapshots the findings in results
+func Create(results []validator.ValidationResult) Baseline {
+	seen := map[Entry]bool{}
+	b := Baseline{Version: 1, Entries: []Entry{}}
+	for _, r := range results {
+		for _, e := range fingerprints(r) {
+			if !seen[e] {
+				seen[e] = true
+				b.Entries = append(b.Entries, e)
+			}
+		}
+	}
+
+	sort.Slice(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.Ecosystem != c.Ecosystem {
+			return a.Ecosystem < c.Ecosystem
+		}
+		if a.Name != c.Name {
+			return a.Name < c.Name
+		}
+		if a.Type != c.Type {
+			return a.Type < c.Type
+		}
+		return a.Reference < c.Reference
+	})
+	return b
+}
+
+// Load reads a baseline file
+func Load(path string) (Baseline, error) {
+	var b Baseline
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	err = json.Unmarshal(data, &b)
+	return b, err
+}
+
+// Save writes the baseline as indented JSON so diffs review well
+func (b Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Apply hides baselined findings: they're dropped from each result, and a
+// result whose every finding is baselined is dropped altogether. A result
+// whose status is baselined but has new findings keeps only the status those
+// call for. It returns the remaining results and the baseline entries that
+// no longer occur.
+func Apply(results []validator.ValidationResult, b Baseline) ([]validator.ValidationResult, []Entry) {
+	accepted := map[Entry]bool{}
+	for _, e := range b.Entries {
+		accepted[e] = true
+	}
+
+	seen := map[Entry]bool{}
+	var kept []validator.ValidationResult
+	for _, r := range results {
+		for _, e := range fingerprints(r) {
+			seen[e] = true
+		}
+
+		var findings []validator.Finding
+		for _, f := range r.Findings {
+			if !accepted[findingEntry(r, f)] {
+				findings = append(findings, f)
+			}
+		}
+		r.Findings = findings
+
+		if r.Status != "safe" && accepted[entry(r, r.Status)] {
+			if len(findings) == 0 {
+				continue
+			}
+			// the accepted status no longer counts, only what the new
+			// findings call for
+			r.Status = findingsStatus(findings)
+		}
+		kept = append(kept, r)
+	}
+
+	var resolved []Entry
+	for _, e := range b.Entries {
+		if !seen[e] {
+			resolved = append(resolved, e)
+		}
+	}
+	return kept, resolved
+}
+
+// fingerprints lists what is wrong with a result: its status when that
+// isn't safe, plus each finding
+func fingerprints(r validator.ValidationResult) []Entry {
+	var entries []Entry
+	if r.Status != "safe" {
+		entries = append(entries, entry(r, r.Status))
+	}
+	for _, f := range r.Findings {
+		entries = append(entries, findingEntry(r, f))
+	}
+	return entries
+}
+
+// findingsStatus is the status findings alone give a result: license
+// findings are reported without changing it, the rest escalate to
+// investigate
+func findingsStatus(findings []validator.Finding) string {
+	status := "safe"
+	for _, f := range findings {
+		switch {
+		case f.Type == "malicious":
+			return "malicious"
+		case !strings.HasPrefix(f.Type, "license_"):
+			status = "investigate"
+		}
+	}
+	return status
+}
+
+// findingEntry fingerprints a finding; a vulnerability's includes the
+// advisory, so accepting one doesn't hide later ones on the same package
+func findingEntry(r validator.ValidationResult, f validator.Finding) Entry {
+	e := entry(r, f.Type)
+	if f.Type == "vulnerability" {
+		e.Reference = f.Reference
+	}
+	return e
+}
+
+func entry(r validator.ValidationResult, kind string) Entry {
+	return Entry{Ecosystem: r.Source, Name: osv.NormalizeName(r.Source, r.Name), Type: kind}
+}
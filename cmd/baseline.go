@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/baseline"
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+)
+
+var baselineOutput string
+
+func init() {
+	baselineCreateCmd.Flags().StringVarP(&baselineOutput, "output", "o", "", "Where to write the baseline (default: <path>/"+baseline.FileName+")")
+	baselineCreateCmd.Flags().BoolVar(&includeLockfiles, "include-lockfiles", false, "Scan npm/yarn lockfiles for all dependencies")
+	baselineCreateCmd.Flags().BoolVar(&includeVendor, "include-vendor", false, "Include vendor directories in scanning (slow!)")
+	baselineCreateCmd.Flags().BoolVar(&scanImports, "scan-imports", false, "Also validate packages imported by source files")
+	baselineCmd.AddCommand(baselineCreateCmd)
+	rootCmd.AddCommand(baselineCmd)
+}
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Manage the baseline of accepted findings",
+}
+
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create [path]",
+	Short: "Snapshot the current findings so only new ones are reported",
+	Long: `Scans the project and records every current finding, keyed by ecosystem,
+package name and finding type, in a file meant to be committed. Scans run
+with --baseline <file> then hide those findings, and --strict no longer fails
+on them, while baselined findings that have since gone away are listed so
+the file can be pruned by running this again.
+
+Use the same scan flags (--include-lockfiles, --check-repos, ...) as the
+scans that will read the baseline.`,
+	Example: `  vibe-validator baseline create .
+  vibe-validator . --baseline .vibe-validator-baseline.json --strict`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+		output := baselineOutput
+		if output == "" {
+			output = filepath.Join(path, baseline.FileName)
+		}
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		v := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		v.Start()
+		defer v.Stop()
+
+		results, err := scanProject(path, cfg, func(msg string) {
+			v.Suffix = " " + msg
+		})
+		v.Stop()
+		if err != nil {
+			fmt.Printf("❌ Scan failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		b := baseline.Create(results)
+		if err := b.Save(output); err != nil {
+			fmt.Printf("❌ Writing baseline failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Baselined %d finding(s) in %s\n", len(b.Entries), output)
+	},
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/reporter"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+)
+
+var checkStdin bool
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkStdin, "stdin", false, "Read package specs from stdin, one per line")
+	rootCmd.AddCommand(checkCmd)
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check <ecosystem:name[@version]>...",
+	Short: "Validate package names before installing them",
+	Long: `Runs the usual validation pipeline on packages named on the command line
+(or on stdin), e.g. the ones an assistant just suggested installing. Exits
+with status 1 if any of them isn't safe.
+
+Ecosystems: npm, pypi, go, php, ruby, rust, actions`,
+	Example: `  vibe-validator check pypi:foo-utils npm:@x/y go:github.com/a/b
+  echo "npm:left-padd@1.0.0" | vibe-validator check --stdin`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var specs []packageSpec
+		for _, arg := range args {
+			spec, err := parseSpec(arg)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			spec.Location = "args"
+			specs = append(specs, spec)
+		}
+
+		if checkStdin {
+			lineNo := 0
+			input := bufio.NewScanner(os.Stdin)
+			for input.Scan() {
+				lineNo++
+				line, _, _ := strings.Cut(input.Text(), "#")
+				for _, field := range strings.Fields(line) {
+					spec, err := parseSpec(field)
+					if err != nil {
+						fmt.Printf("❌ stdin:%d: %v\n", lineNo, err)
+						os.Exit(1)
+					}
+					spec.Location = fmt.Sprintf("stdin:%d", lineNo)
+					specs = append(specs, spec)
+				}
+			}
+			// a read error or an overlong line would otherwise cut the list short
+			if err := input.Err(); err != nil {
+				fmt.Printf("❌ stdin:%d: %v\n", lineNo+1, err)
+				os.Exit(1)
+			}
+		}
+
+		if len(specs) == 0 {
+			fmt.Println("❌ No packages to check")
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfig(".")
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		v := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		v.Start()
+		defer v.Stop()
+
+		results, err := checkSpecs(specs, cfg, func(msg string) {
+			v.Suffix = " " + msg
+		})
+		v.Stop()
+		if err != nil {
+			fmt.Printf("❌ Checks failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		// everything asked about is worth showing, safe or not
+		reporter.PrintReport(results, reporter.Options{Verbosity: max(verbosity, 1)})
+
+		for _, r := range results {
+			if r.Status != "safe" {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// checkSpecs runs the validation pipeline over package specs
+func checkSpecs(specs []packageSpec, cfg config.Config, progress func(string)) ([]validator.ValidationResult, error) {
+	deps, versions := specDeps(specs)
+
+	progress("Validating packages...")
+	results := validator.ValidatePackages(deps, versions)
+	err := runChecks(results, deps, cfg, checkOptions{Repos: checkRepos, Licenses: checkLicenses}, progress)
+	return results, err
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+)
+
+// checkOptions turns on the checks that cost extra API calls
+type checkOptions struct {
+	Repos    bool
+	Licenses bool
+}
+
+// feeds holds the advisory databases and blocklist named by flags
+type feeds struct {
+	osv       *osv.DB
+	malicious *osv.DB
+	blocklist validator.Blocklist
+}
+
+// loadFeeds reads the databases enabled by flags, keeping only entries for
+// the wanted dependencies (all of them when wanted is nil)
+func loadFeeds(wanted scanner.AllDeps, progress func(string)) (feeds, error) {
+	var f feeds
+	var err error
+
+	if osvDBPath != "" {
+		progress("Loading OSV database...")
+		if f.osv, err = osv.Load(osvDBPath, wanted); err != nil {
+			return f, fmt.Errorf("OSV database: %w", err)
+		}
+	}
+	if maliciousDBPath != "" {
+		progress("Loading malicious package feed...")
+		if f.malicious, err = osv.Load(maliciousDBPath, wanted); err != nil {
+			return f, fmt.Errorf("malicious package feed: %w", err)
+		}
+	}
+	if blocklistPath != "" {
+		if f.blocklist, err = validator.LoadBlocklist(blocklistPath); err != nil {
+			return f, fmt.Errorf("blocklist: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// runChecks runs the post-validation checks enabled by flags and config,
+// then scores the results. progress is told what is happening so callers
+// can update a spinner.
+func runChecks(results []validator.ValidationResult, deps scanner.AllDeps, cfg config.Config, opts checkOptions, progress func(string)) error {
+	f, err := loadFeeds(deps, progress)
+	if err != nil {
+		return err
+	}
+	applyChecks(results, cfg, opts, f, progress)
+	return nil
+}
+
+// applyChecks is runChecks with the feeds already loaded, for long-running
+// commands that shouldn't reload them on every request
+func applyChecks(results []validator.ValidationResult, cfg config.Config, opts checkOptions, f feeds, progress func(string)) {
+	if opts.Repos {
+		progress("Checking source repositories...")
+		validator.CheckRepositories(results, cfg.Repository)
+	}
+	if opts.Licenses {
+		progress("Checking licenses...")
+		validator.CheckLicenses(results, cfg.License)
+	}
+
+	if f.osv != nil {
+		validator.CheckVulnerabilities(results, f.osv)
+	}
+	if f.malicious != nil || f.blocklist != nil {
+		progress("Checking malicious package feeds...")
+		validator.CheckMalicious(results, f.malicious, f.blocklist)
+	}
+
+	validator.ScoreResults(results, cfg.Score)
+}
+
+// scanProject scans a project with the scan flags and runs the full
+// validation pipeline over what it finds
+func scanProject(path string, cfg config.Config, progress func(string)) ([]validator.ValidationResult, error) {
+	progress("Scanning dependencies...")
+	deps, versions, err := scanner.ScanDependencies(path, scanner.ScanOptions{
+		IncludeLockfiles: includeLockfiles,
+		IncludeVendor:    includeVendor,
+		IncludeImports:   scanImports,
+		ResolveGoModule:  validator.ResolveGoModule,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	progress("Validating dependencies...")
+	results := validator.ValidatePackages(deps, versions)
+	err = runChecks(results, deps, cfg, checkOptions{Repos: checkRepos, Licenses: checkLicenses}, progress)
+	return results, err
+}
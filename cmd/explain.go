@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/reporter"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+)
+
+var explainPath string
+
+func init() {
+	explainCmd.Flags().StringVar(&explainPath, "path", ".", "Project to search for declarations of the package")
+	rootCmd.AddCommand(explainCmd)
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <ecosystem> <name>[@version]",
+	Short: "Show every signal collected for a single dependency",
+	Long: `Looks up one package with every check enabled and prints the registry
+metadata, where the package is declared under --path, each risk signal and
+the thresholds applied to it, and a plain-language verdict.
+
+Ecosystems: npm, pypi, go, php, ruby, rust, actions`,
+	Example: `  vibe-validator explain npm left-pad
+  vibe-validator explain pypi requests@2.28.1 --osv-db ./osv`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		eco := args[0]
+		name, version := splitVersion(args[1])
+		if _, ok := osv.Ecosystems[eco]; !ok {
+			fmt.Printf("❌ Unknown ecosystem %q\n", eco)
+			os.Exit(1)
+		}
+		fmt.Printf("[oo] Explaining %s:%s\n\n", eco, args[1])
+
+		cfg, err := loadConfig(explainPath)
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+		s.Start()
+		defer s.Stop()
+
+		result, err := explainPackage(explainPath, eco, name, version, cfg, func(msg string) {
+			s.Suffix = " " + msg
+		})
+		s.Stop()
+		if err != nil {
+			fmt.Printf("❌ Checks failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		reporter.PrintExplanation(result, cfg.Score)
+	},
+}
+
+// explainPackage validates one package with every check turned on, using
+// the project at path to find where it is declared
+func explainPackage(path, eco, name, version string, cfg config.Config, progress func(string)) (validator.ValidationResult, error) {
+	progress("Looking for declarations...")
+	paths, versions := findDeclarations(path, eco, name)
+	if version != "" {
+		versions = []string{version}
+	}
+
+	progress("Fetching registry metadata...")
+	result, ok := validator.ValidatePackage(eco, name, paths, versions)
+	if !ok {
+		return result, fmt.Errorf("unknown ecosystem %q", eco)
+	}
+	results := []validator.ValidationResult{result}
+	deps := scanner.AllDeps{eco: {name: paths}}
+	err := runChecks(results, deps, cfg, checkOptions{Repos: true, Licenses: true}, progress)
+	return results[0], err
+}
+
+// findDeclarations scans the project, lockfiles included, for every file
+// declaring the package and the versions pinned for it
+func findDeclarations(path, eco, name string) ([]string, []string) {
+	deps, versions, err := scanner.ScanDependencies(path, scanner.ScanOptions{IncludeLockfiles: true})
+	if err != nil {
+		return nil, nil
+	}
+
+	want := osv.NormalizeName(eco, name)
+	for declared, paths := range deps[eco] {
+		if osv.NormalizeName(eco, declared) == want {
+			return paths, versions[eco][declared]
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/reporter"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/briandowns/spinner"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var guardYes bool
+
+func init() {
+	guardCmd.Flags().BoolVarP(&guardYes, "yes", "y", false, "Run the command even if packages fail validation, without prompting")
+	// everything after the package manager's name belongs to it
+	guardCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(guardCmd)
+}
+
+var guardCmd = &cobra.Command{
+	Use:   "guard -- <package manager command>",
+	Short: "Validate packages before a package manager installs them",
+	Long: `Wraps a package manager install command, validates the packages it names
+and only runs it if they all pass. When something fails you're asked whether
+to carry on; without a terminal to ask on the command is refused unless
+--yes is given.
+
+Understands npm install, yarn add, pnpm add, pip install, uv add,
+uv pip install, poetry add, go get, go install, cargo add, cargo install,
+composer require, gem install and bundle add. Anything else is run as-is.`,
+	Example: `  vibe-validator guard -- npm install left-padd
+  vibe-validator guard -- pip install requests==2.31.0
+  alias npm='vibe-validator guard -- npm'`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var specs []packageSpec
+		for _, pkg := range scanner.ParseInstallCommand(args) {
+			specs = append(specs, packageSpec{
+				Ecosystem: pkg.Ecosystem,
+				Name:      pkg.Name,
+				Version:   pkg.Version,
+				Location:  "command",
+			})
+		}
+
+		if len(specs) > 0 && !guardPasses(specs) {
+			switch {
+			case guardYes:
+				fmt.Println("⚠️  Continuing despite failed checks (--yes)")
+			case !term.IsTerminal(int(os.Stdin.Fd())):
+				fmt.Println("❌ Refusing to install packages that failed validation (use --yes to override)")
+				os.Exit(1)
+			case !confirm("Install anyway?"):
+				fmt.Println("❌ Install cancelled")
+				os.Exit(1)
+			}
+		}
+
+		os.Exit(runCommand(args))
+	},
+}
+
+// guardPasses validates the packages a command would install, reporting
+// any that aren't safe
+func guardPasses(specs []packageSpec) bool {
+	cfg, err := loadConfig(".")
+	if err != nil {
+		fmt.Printf("❌ Config failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	v := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
+	v.Start()
+	defer v.Stop()
+
+	// go get accepts package paths, but the proxy only knows modules
+	for i, spec := range specs {
+		if spec.Ecosystem == "go" {
+			v.Suffix = " Resolving " + spec.Name + "..."
+			specs[i].Name, _ = validator.ResolveGoModule(spec.Name)
+		}
+	}
+
+	results, err := checkSpecs(specs, cfg, func(msg string) {
+		v.Suffix = " " + msg
+	})
+	v.Stop()
+	if err != nil {
+		fmt.Printf("❌ Checks failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	passed := true
+	for _, r := range results {
+		if r.Status != "safe" {
+			passed = false
+		}
+	}
+	if !passed || verbosity > 0 {
+		reporter.PrintReport(results, reporter.Options{Verbosity: max(verbosity, 1)})
+	}
+	return passed
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// runCommand runs the wrapped command attached to our terminal and returns
+// its exit code
+func runCommand(args []string) int {
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Printf("❌ %v\n", err)
+		return 127
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/gitscan"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies hooks we wrote, so reinstalling can replace them
+const hookMarker = "# installed by vibe-validator"
+
+var hookForce bool
+
+func init() {
+	hookInstallCmd.Flags().BoolVar(&hookForce, "force", false, "Replace an existing pre-commit hook")
+	hookRunCmd.Flags().BoolVar(&includeLockfiles, "include-lockfiles", false, "Also check staged lockfiles")
+	hookCmd.AddCommand(hookInstallCmd, hookRunCmd)
+	rootCmd.AddCommand(hookCmd)
+}
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Check dependencies in a git pre-commit hook",
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a git pre-commit hook that checks staged manifests",
+	Long: `Writes a pre-commit hook into the current repository that runs
+"vibe-validator hook run". Projects using the pre-commit framework can use
+the vibe-validator hook from .pre-commit-hooks.yaml instead.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := exec.Command("git", "rev-parse", "--git-path", "hooks/pre-commit").Output()
+		if err != nil {
+			fmt.Println("❌ Not inside a git repository")
+			os.Exit(1)
+		}
+		hookPath := strings.TrimSpace(string(out))
+
+		if existing, err := os.ReadFile(hookPath); err == nil && !bytes.Contains(existing, []byte(hookMarker)) && !hookForce {
+			fmt.Printf("❌ %s already exists; use --force to replace it\n", hookPath)
+			os.Exit(1)
+		}
+
+		// the hook runs without the caller's PATH guarantees, so pin the binary
+		binary, err := os.Executable()
+		if err != nil {
+			binary = "vibe-validator"
+		}
+		script := fmt.Sprintf("#!/bin/sh\n%s\nexec %q hook run\n", hookMarker, binary)
+
+		if err := os.MkdirAll(filepath.Dir(hookPath), 0o755); err != nil {
+			fmt.Printf("❌ Installing hook failed: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(hookPath, []byte(script), 0o755); err != nil {
+			fmt.Printf("❌ Installing hook failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Installed %s\n", hookPath)
+	},
+}
+
+var hookRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Check dependencies introduced by the staged changes",
+	Long: `Reads the staged manifests, Dockerfiles, workflows, scripts and
+notebooks from the git index (not the working tree), compares them with HEAD,
+and validates only the dependencies the commit would add or re-pin. Exits
+with status 1, blocking the commit, if any of them isn't safe.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := scanner.ScanOptions{IncludeLockfiles: includeLockfiles, ResolveGoModule: validator.ResolveGoModule}
+
+		staged, stagedVersions, err := gitscan.Scan(".", gitscan.Index, opts)
+		if err != nil {
+			fmt.Printf("❌ vibe-validator: %v\n", err)
+			os.Exit(1)
+		}
+
+		// the first commit has no HEAD to compare against
+		head, headVersions := scanner.AllDeps{}, scanner.AllVersions{}
+		if exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Run() == nil {
+			if head, headVersions, err = gitscan.Scan(".", "HEAD", opts); err != nil {
+				fmt.Printf("❌ vibe-validator: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		deps, versions, changes := gitscan.Diff(head, headVersions, staged, stagedVersions)
+		if len(changes) == 0 {
+			return
+		}
+
+		cfg, err := loadConfig(".")
+		if err != nil {
+			fmt.Printf("❌ vibe-validator: config: %v\n", err)
+			os.Exit(1)
+		}
+		results := validator.ValidatePackages(deps, versions)
+		if err := runChecks(results, deps, cfg, checkOptions{Repos: checkRepos, Licenses: checkLicenses}, quiet); err != nil {
+			fmt.Printf("❌ vibe-validator: %v\n", err)
+			os.Exit(1)
+		}
+
+		blocked := 0
+		for _, r := range results {
+			if r.Status == "safe" {
+				continue
+			}
+			blocked++
+			fmt.Printf("✗ %s:%s (%s, %s): %s\n", r.Source, r.Name, strings.Join(r.Paths, ", "), r.Status, r.Details)
+		}
+		if blocked > 0 {
+			noun := "dependencies"
+			if blocked == 1 {
+				noun = "dependency"
+			}
+			fmt.Printf("vibe-validator blocked this commit: %d new %s failed validation.\n", blocked, noun)
+			fmt.Println("Run `vibe-validator explain <ecosystem> <name>` for details, or commit with --no-verify to skip.")
+			os.Exit(1)
+		}
+	},
+}
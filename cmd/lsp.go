@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/lsp"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/spf13/cobra"
+)
+
+var lspCacheTTL time.Duration
+
+func init() {
+	lspCmd.Flags().DurationVar(&lspCacheTTL, "cache-ttl", time.Hour, "How long to remember a dependency's verdict")
+	rootCmd.AddCommand(lspCmd)
+}
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server that flags suspicious dependencies as you type",
+	Long: `Speaks the Language Server Protocol over stdin/stdout. Open a manifest
+(package.json, requirements.txt, go.mod, Cargo.toml, composer.json, Gemfile
+or their lockfiles) and every dependency that isn't safe gets a diagnostic on
+its entry; hover over a dependency for its registry details.
+
+Flags such as --osv-db, --malicious-db and --check-repos apply to every check.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+		f, err := loadFeeds(nil, quiet)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		opts := checkOptions{Repos: checkRepos, Licenses: checkLicenses}
+
+		server := lsp.NewServer("vibe-validator", version, lspCacheTTL, func(eco, name, pinned string) validator.ValidationResult {
+			var versions []string
+			if pinned != "" {
+				versions = []string{pinned}
+			}
+			result, _ := validator.ValidatePackage(eco, name, []string{"editor"}, versions)
+			results := []validator.ValidationResult{result}
+			applyChecks(results, cfg, opts, f, quiet)
+			return results[0]
+		})
+		if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Language server failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
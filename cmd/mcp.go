@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Kelcode-Dev/vibe-validator/mcp"
+	"github.com/Kelcode-Dev/vibe-validator/reporter"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server on stdio for AI coding assistants",
+	Long: `Speaks MCP over stdin/stdout so an AI agent can check dependencies before
+it edits a manifest. Tools: check_packages, scan_project, explain_package.
+
+Register it with your assistant as a stdio server running "vibe-validator mcp".
+Flags such as --osv-db, --malicious-db and --check-repos apply to every call.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		server := mcp.NewServer("vibe-validator", version, mcpTools())
+		if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ MCP server failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// quiet discards progress messages; stdout belongs to the protocol
+func quiet(string) {}
+
+func mcpTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name: "check_packages",
+			Description: "Check that packages exist and look legitimate before adding them to a project. " +
+				"Call this before installing or adding any dependency to a manifest. " +
+				"Packages are given as ecosystem:name[@version], ecosystem being npm, pypi, go, php, ruby, rust or actions.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"packages": map[string]any{
+						"type":        "array",
+						"items":       map[string]any{"type": "string"},
+						"minItems":    1,
+						"description": `Package specs, e.g. ["pypi:requests", "npm:@types/node@20.1.0"]`,
+					},
+				},
+				"required": []string{"packages"},
+			},
+			Handler: func(raw json.RawMessage) (any, error) {
+				var args struct {
+					Packages []string `json:"packages"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return nil, err
+				}
+				// an empty answer would read as "all safe"
+				if len(args.Packages) == 0 {
+					return nil, errors.New("no packages to check")
+				}
+
+				var specs []packageSpec
+				for _, p := range args.Packages {
+					spec, err := parseSpec(p)
+					if err != nil {
+						return nil, err
+					}
+					spec.Location = "request"
+					specs = append(specs, spec)
+				}
+
+				cfg, err := loadConfig(".")
+				if err != nil {
+					return nil, err
+				}
+				results, err := checkSpecs(specs, cfg, quiet)
+				if err != nil {
+					return nil, err
+				}
+				return resultSummary(results), nil
+			},
+		},
+		{
+			Name:        "scan_project",
+			Description: "Scan a project's manifests (package.json, requirements.txt, go.mod, composer.json, Gemfile, Cargo.toml) and validate every dependency.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path":              map[string]any{"type": "string", "description": "Project directory"},
+					"include_lockfiles": map[string]any{"type": "boolean", "description": "Also validate everything pinned in lockfiles"},
+					"include_safe":      map[string]any{"type": "boolean", "description": "Include dependencies that passed"},
+				},
+				"required": []string{"path"},
+			},
+			Handler: func(raw json.RawMessage) (any, error) {
+				var args struct {
+					Path             string `json:"path"`
+					IncludeLockfiles bool   `json:"include_lockfiles"`
+					IncludeSafe      bool   `json:"include_safe"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return nil, err
+				}
+				if _, err := os.Stat(args.Path); err != nil {
+					return nil, err
+				}
+
+				cfg, err := loadConfig(args.Path)
+				if err != nil {
+					return nil, err
+				}
+				deps, versions, err := scanner.ScanDependencies(args.Path, scanner.ScanOptions{IncludeLockfiles: args.IncludeLockfiles, ResolveGoModule: validator.ResolveGoModule})
+				if err != nil {
+					return nil, err
+				}
+				results := validator.ValidatePackages(deps, versions)
+				if err := runChecks(results, deps, cfg, checkOptions{Repos: checkRepos, Licenses: checkLicenses}, quiet); err != nil {
+					return nil, err
+				}
+
+				summary := resultSummary(results)
+				if !args.IncludeSafe {
+					var flagged []validator.ValidationResult
+					for _, r := range results {
+						if r.Status != "safe" {
+							flagged = append(flagged, r)
+						}
+					}
+					summary["results"] = flagged
+				}
+				return summary, nil
+			},
+		},
+		{
+			Name:        "explain_package",
+			Description: "Explain why a package was flagged: every signal collected, its score contribution, where it is declared, and a plain-language verdict.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"ecosystem": map[string]any{"type": "string", "enum": []string{"npm", "pypi", "go", "php", "ruby", "rust", "actions"}},
+					"name":      map[string]any{"type": "string"},
+					"version":   map[string]any{"type": "string"},
+					"path":      map[string]any{"type": "string", "description": "Project to search for declarations (default: current directory)"},
+				},
+				"required": []string{"ecosystem", "name"},
+			},
+			Handler: func(raw json.RawMessage) (any, error) {
+				var args struct {
+					Ecosystem string `json:"ecosystem"`
+					Name      string `json:"name"`
+					Version   string `json:"version"`
+					Path      string `json:"path"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return nil, err
+				}
+				if args.Path == "" {
+					args.Path = "."
+				}
+
+				cfg, err := loadConfig(args.Path)
+				if err != nil {
+					return nil, err
+				}
+				result, err := explainPackage(args.Path, args.Ecosystem, args.Name, args.Version, cfg, quiet)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{
+					"result":  result,
+					"verdict": reporter.Verdict(result, cfg.Score),
+				}, nil
+			},
+		},
+	}
+}
+
+// resultSummary wraps results with an overall pass/fail so an agent can
+// act on the answer without reading every entry
+func resultSummary(results []validator.ValidationResult) map[string]any {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return map[string]any{
+		"ok":      counts["safe"] == len(results),
+		"counts":  counts,
+		"results": results,
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/proxy"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	proxyListen   string
+	proxyNPM      string
+	proxyPyPI     string
+	proxyCacheTTL time.Duration
+)
+
+func init() {
+	proxyCmd.Flags().StringVar(&proxyListen, "listen", "127.0.0.1:8181", "Address to listen on")
+	proxyCmd.Flags().StringVar(&proxyNPM, "npm-registry", "https://registry.npmjs.org", "Upstream npm registry")
+	proxyCmd.Flags().StringVar(&proxyPyPI, "pypi-index", "https://pypi.org", "Upstream PyPI (serving /simple and /pypi/<name>/json)")
+	proxyCmd.Flags().DurationVar(&proxyCacheTTL, "cache-ttl", time.Hour, "How long to remember a package's verdict")
+	rootCmd.AddCommand(proxyCmd)
+}
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Run a local npm/PyPI registry proxy that blocks packages failing validation",
+	Long: `Fronts the npm registry and PyPI, validating every package a client asks
+about before passing its metadata on. Packages that aren't safe get a 403, so
+the install fails before anything is downloaded. Releases listed in the
+--malicious-db feed are stripped from otherwise acceptable packages, and npm
+tarball URLs are rewritten to come back through the proxy.
+
+Point your tools at it once:
+  npm config set registry http://127.0.0.1:8181/npm/
+  pip config set global.index-url http://127.0.0.1:8181/pypi/simple/`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig(".")
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		// private packages only exist on the configured upstreams
+		validator.NPMRegistry = proxyNPM
+		validator.PyPIIndex = proxyPyPI
+
+		// feeds are loaded whole; there's no manifest to narrow them down
+		f, err := loadFeeds(nil, func(msg string) { fmt.Println(msg) })
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		opts := checkOptions{Repos: checkRepos, Licenses: checkLicenses}
+
+		handler := proxy.New(proxy.Options{
+			NPMRegistry: proxyNPM,
+			PyPIIndex:   proxyPyPI,
+			CacheTTL:    proxyCacheTTL,
+			Log:         os.Stdout,
+			Check: func(eco, name string) validator.ValidationResult {
+				result, _ := validator.ValidatePackage(eco, name, []string{"proxy"}, nil)
+				results := []validator.ValidationResult{result}
+				applyChecks(results, cfg, opts, f, quiet)
+				return results[0]
+			},
+			BlockedVersion: func(eco, name, version string) (string, bool) {
+				if f.malicious == nil {
+					return "", false
+				}
+				var ids []string
+				for _, adv := range f.malicious.Query(eco, name, version) {
+					if adv.Malicious() {
+						ids = append(ids, adv.ID)
+					}
+				}
+				return strings.Join(ids, ", "), len(ids) > 0
+			},
+		})
+
+		fmt.Printf("🛡️  Registry proxy listening on http://%s (npm: /npm/, PyPI: /pypi/simple/)\n", proxyListen)
+		if err := http.ListenAndServe(proxyListen, handler); err != nil {
+			fmt.Printf("❌ Proxy failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Kelcode-Dev/vibe-validator/reporter"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/spf13/cobra"
+)
+
+var failUndeclared bool
+
+func init() {
+	reconcileCmd.Flags().BoolVar(&failUndeclared, "fail-undeclared", false, "Exit with status 1 if any import isn't declared in a manifest")
+	reconcileCmd.Flags().BoolVar(&includeVendor, "include-vendor", false, "Include vendor directories in scanning (slow!)")
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile <path>",
+	Short: "Compare what manifests declare with what the code imports",
+	Long: `Matches the Python, JavaScript/TypeScript and Go imports in a project
+against its manifests, listing imports that no manifest declares (phantom
+dependencies, often hallucinated) and declared dependencies that nothing
+imports (dead weight). Nothing is looked up in the registries; validate
+undeclared imports with "vibe-validator <path> --scan-imports".`,
+	Example: `  vibe-validator reconcile .
+  vibe-validator reconcile . --fail-undeclared`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rec, err := scanner.Reconcile(args[0], includeVendor)
+		if err != nil {
+			fmt.Printf("❌ Scan failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		reporter.PrintReconciliation(rec)
+		if failUndeclared && len(rec.Undeclared) > 0 {
+			os.Exit(1)
+		}
+	},
+}
@@ -3,8 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/Kelcode-Dev/vibe-validator/baseline"
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/gitscan"
 	"github.com/Kelcode-Dev/vibe-validator/reporter"
 	"github.com/Kelcode-Dev/vibe-validator/scanner"
 	"github.com/Kelcode-Dev/vibe-validator/validator"
@@ -12,30 +17,82 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// version is overridden at build time with -ldflags "-X .../cmd.version=..."
+var version = "0.2.0"
+
 var (
 	includeLockfiles bool
 	includeVendor    bool
+	scanImports      bool
+	checkRepos       bool
+	checkLicenses    bool
+	configPath       string
+	osvDBPath        string
+	maliciousDBPath  string
+	blocklistPath    string
+	minScore         float64
+	baselinePath     string
+	strict           bool
+	sinceRef         string
 	verbosity        int = 0
 )
 
 func init() {
 	rootCmd.Flags().BoolVar(&includeLockfiles, "include-lockfiles", false, "Scan npm/yarn lockfiles for all dependencies")
 	rootCmd.Flags().BoolVar(&includeVendor, "include-vendor", false, "Include vendor directories in scanning (slow!)")
+	rootCmd.Flags().BoolVar(&scanImports, "scan-imports", false, "Also validate packages imported by source files, even if no manifest declares them")
+	rootCmd.PersistentFlags().BoolVar(&checkRepos, "check-repos", false, "Verify each package's source repository via the forge APIs")
+	rootCmd.PersistentFlags().BoolVar(&checkLicenses, "check-licenses", false, "Report missing, unknown or disallowed licenses (policy from the config file)")
+	rootCmd.PersistentFlags().StringVar(&osvDBPath, "osv-db", "", "Directory of OSV.dev exports (<Ecosystem>/all.zip) to check pinned versions against")
+	rootCmd.PersistentFlags().StringVar(&maliciousDBPath, "malicious-db", "", "Local copy of the OpenSSF malicious-packages repository (or any OSV MAL- entries)")
+	rootCmd.PersistentFlags().StringVar(&validator.GitHubAPI, "github-api", validator.GitHubAPI, "GitHub API base URL to check actions against (GitHub Enterprise Server: https://<host>/api/v3)")
+	rootCmd.PersistentFlags().StringVar(&blocklistPath, "blocklist", "", "File of ecosystem:name packages to treat as malicious")
+	rootCmd.Flags().Float64Var(&minScore, "min-score", 0, "Only report dependencies with at least this risk score (0-100)")
+	rootCmd.Flags().StringVar(&baselinePath, "baseline", "", "Hide findings recorded in this baseline file (see: baseline create)")
+	rootCmd.Flags().StringVar(&sinceRef, "since", "", "Only validate dependencies added or re-pinned since this git ref (compares manifests at the ref and HEAD)")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "Exit with status 1 if any dependency isn't safe")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file (default: <path>/"+config.FileName+" if present)")
 	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Increase verbosity level")
+
+	// --since only diffs what git can show for both refs, which doesn't
+	// include source imports
+	rootCmd.MarkFlagsMutuallyExclusive("since", "scan-imports")
+}
+
+// loadConfig reads --config, or the project's own config file, or falls
+// back to the defaults
+func loadConfig(projectPath string) (config.Config, error) {
+	if configPath != "" {
+		return config.Load(configPath)
+	}
+	local := filepath.Join(projectPath, config.FileName)
+	if _, err := os.Stat(local); err == nil {
+		return config.Load(local)
+	}
+	return config.Default(), nil
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "vibe-validator [path]",
-	Short: "Scan project dependencies for sketchy vibes",
-	Args:  cobra.ExactArgs(1),
+	Use:     "vibe-validator [path]",
+	Short:   "Scan project dependencies for sketchy vibes",
+	Version: version,
+	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
 		//create a cli logo for the top of the output
 		fmt.Println("[oo] Scanning:", path)
 
+		cfg, err := loadConfig(path)
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+
 		opts := scanner.ScanOptions{
 			IncludeLockfiles: includeLockfiles,
 			IncludeVendor:    includeVendor,
+			IncludeImports:   scanImports,
+			ResolveGoModule:  validator.ResolveGoModule,
 			Verbosity:        verbosity,
 		}
 
@@ -46,7 +103,14 @@ var rootCmd = &cobra.Command{
 			defer s.Stop()
 		}
 
-		deps, err := scanner.ScanDependencies(path, opts)
+		var deps scanner.AllDeps
+		var versions scanner.AllVersions
+		var changes map[string]gitscan.Change
+		if sinceRef != "" {
+			deps, versions, changes, err = scanSince(path, sinceRef, opts)
+		} else {
+			deps, versions, err = scanner.ScanDependencies(path, opts)
+		}
 		if verbosity < 2 {
 			s.Stop()
 		}
@@ -60,14 +124,85 @@ var rootCmd = &cobra.Command{
 		v.Start()
 		defer v.Stop()
 
-		results := validator.ValidatePackages(deps)
+		results := validator.ValidatePackages(deps, versions)
+		err = runChecks(results, deps, cfg, checkOptions{Repos: checkRepos, Licenses: checkLicenses}, func(msg string) {
+			v.Suffix = " " + msg
+		})
+		if err != nil {
+			v.Stop()
+			fmt.Printf("❌ Checks failed: %v\n", err)
+			os.Exit(1)
+		}
 		v.Stop()
 
+		for i := range results {
+			if change, found := changes[gitscan.Key(results[i].Source, results[i].Name)]; found {
+				results[i].Change = describeChange(change)
+			}
+		}
+
+		var resolved []baseline.Entry
+		if baselinePath != "" {
+			b, err := baseline.Load(baselinePath)
+			if err != nil {
+				fmt.Printf("❌ Baseline failed: %v\n", err)
+				os.Exit(1)
+			}
+			all := len(results)
+			results, resolved = baseline.Apply(results, b)
+			if hidden := all - len(results); hidden > 0 {
+				fmt.Printf("Hiding %d baselined dependencies\n", hidden)
+			}
+		}
+
 		fmt.Println("Validation complete, prepping report...")
-		reporter.PrintReport(results, verbosity)
+		reporter.PrintReport(results, reporter.Options{Verbosity: verbosity, MinScore: minScore})
+		if sinceRef == "" {
+			// in diff mode a baselined package that wasn't touched is
+			// missing from the results, not resolved
+			reporter.PrintResolved(resolved)
+		}
+
+		if strict {
+			for _, r := range results {
+				if r.Status != "safe" && r.Score >= minScore {
+					os.Exit(1)
+				}
+			}
+		}
 	},
 }
 
+// scanSince reads the manifests at ref and at HEAD from git and keeps the
+// dependencies HEAD adds or re-pins
+func scanSince(path, ref string, opts scanner.ScanOptions) (scanner.AllDeps, scanner.AllVersions, map[string]gitscan.Change, error) {
+	oldDeps, oldVersions, err := gitscan.Scan(path, ref, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	newDeps, newVersions, err := gitscan.Scan(path, "HEAD", opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	deps, versions, changes := gitscan.Diff(oldDeps, oldVersions, newDeps, newVersions)
+	if opts.Verbosity >= 2 {
+		fmt.Printf("%d dependencies added or changed since %s\n", len(changes), ref)
+	}
+	return deps, versions, changes, nil
+}
+
+// describeChange labels a result in diff mode
+func describeChange(c gitscan.Change) string {
+	if c.Kind == "added" {
+		return "added"
+	}
+	if len(c.From) == 0 {
+		return "version pinned"
+	}
+	return "version changed from " + strings.Join(c.From, ", ")
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println("❌ Error:", err)
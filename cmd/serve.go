@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/api"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen   string
+	serveMaxBody  int64
+	serveCacheTTL time.Duration
+)
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().Int64Var(&serveMaxBody, "max-body", 10<<20, "Largest request body (and unpacked manifests in a tarball) accepted, in bytes")
+	serveCmd.Flags().DurationVar(&serveCacheTTL, "cache-ttl", time.Hour, "How long registry lookups are shared between requests")
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP API for scanning projects and checking packages",
+	Long: `Serves the validation pipeline over HTTP, returning JSON reports:
+
+  POST /v1/scan    project tarball (.tar or .tar.gz) or multipart manifest
+                   uploads; ?lockfiles=true and ?vendor=true as for scans
+  POST /v1/check   {"packages": ["npm:left-pad@1.3.0", "pypi:requests"]}
+  GET  /healthz    liveness
+  GET  /readyz     readiness (503 until advisory feeds have loaded)
+  GET  /metrics    Prometheus metrics
+
+Registry lookups are cached across requests for --cache-ttl. Flags such as
+--osv-db, --malicious-db and --check-repos apply to every request.`,
+	Example: `  vibe-validator serve --listen :8080 --osv-db ./osv
+  tar czf - . | curl --data-binary @- -H 'Content-Type: application/gzip' localhost:8080/v1/scan
+  curl -F file=@package.json localhost:8080/v1/scan`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig(".")
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+		opts := checkOptions{Repos: checkRepos, Licenses: checkLicenses}
+		cache := validator.NewCache(serveCacheTTL)
+
+		// take traffic straight away, but only report ready once the feeds
+		// (which can take a while) are in memory
+		var f feeds
+		var ready atomic.Bool
+		go func() {
+			loaded, err := loadFeeds(nil, func(msg string) { fmt.Println(msg) })
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			f = loaded
+			ready.Store(true)
+			fmt.Println("✅ Ready")
+		}()
+
+		server := api.New(api.Options{
+			MaxBodyBytes: serveMaxBody,
+			Ready:        ready.Load,
+			CacheStats:   cache.Stats,
+			Validate: func(deps scanner.AllDeps, versions scanner.AllVersions) ([]validator.ValidationResult, error) {
+				results := cache.ValidatePackages(deps, versions)
+				applyChecks(results, cfg, opts, f, quiet)
+				return results, nil
+			},
+			ParseSpec: func(s string) (string, string, string, error) {
+				spec, err := parseSpec(s)
+				return spec.Ecosystem, spec.Name, spec.Version, err
+			},
+		})
+
+		fmt.Printf("🌐 API listening on http://%s\n", serveListen)
+		httpServer := &http.Server{Addr: serveListen, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+		if err := httpServer.ListenAndServe(); err != nil {
+			fmt.Printf("❌ Server failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+)
+
+// packageSpec is a package named on the command line rather than found in
+// a manifest
+type packageSpec struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	Location  string // where the spec came from, reported as its path
+}
+
+func (p packageSpec) String() string {
+	s := p.Ecosystem + ":" + p.Name
+	if p.Version != "" {
+		s += "@" + p.Version
+	}
+	return s
+}
+
+// parseSpec reads "ecosystem:name[@version]", e.g. "npm:@x/y@1.0.0"
+func parseSpec(s string) (packageSpec, error) {
+	eco, rest, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || rest == "" {
+		return packageSpec{}, fmt.Errorf("%q: expected ecosystem:name", s)
+	}
+	if _, known := osv.Ecosystems[eco]; !known {
+		return packageSpec{}, fmt.Errorf("%q: unknown ecosystem %q", s, eco)
+	}
+
+	name, version := splitVersion(rest)
+	return packageSpec{Ecosystem: eco, Name: name, Version: version}, nil
+}
+
+// splitVersion separates "name@version", minding npm scopes ("@scope/name")
+func splitVersion(spec string) (string, string) {
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		return spec[:at], spec[at+1:]
+	}
+	return spec, ""
+}
+
+// specDeps turns specs into the scanner's shape so they can go through the
+// same validation pipeline
+func specDeps(specs []packageSpec) (map[string]map[string][]string, map[string]map[string][]string) {
+	deps := map[string]map[string][]string{}
+	versions := map[string]map[string][]string{}
+
+	for _, spec := range specs {
+		if deps[spec.Ecosystem] == nil {
+			deps[spec.Ecosystem] = map[string][]string{}
+			versions[spec.Ecosystem] = map[string][]string{}
+		}
+		deps[spec.Ecosystem][spec.Name] = append(deps[spec.Ecosystem][spec.Name], spec.Location)
+		if spec.Version != "" {
+			versions[spec.Ecosystem][spec.Name] = append(versions[spec.Ecosystem][spec.Name], spec.Version)
+		}
+	}
+	return deps, versions
+}
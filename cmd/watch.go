@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/reporter"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+var watchSummary bool
+
+func init() {
+	watchCmd.Flags().BoolVar(&watchSummary, "summary", false, "Print one line per change instead of a report, e.g. for desktop notifications")
+	watchCmd.Flags().BoolVar(&includeLockfiles, "include-lockfiles", false, "Watch npm/yarn lockfiles too")
+	watchCmd.Flags().BoolVar(&includeVendor, "include-vendor", false, "Watch vendor directories too")
+	rootCmd.AddCommand(watchCmd)
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <path>",
+	Short: "Re-validate as manifests change",
+	Long: `Watches a project for changes to any manifest or lockfile the scanners
+understand. When one changes only that file is re-parsed, and only names not
+seen before are validated, so feedback arrives while an assistant is still
+editing. Dependencies already present when watching starts are assumed to
+have been checked by a full scan; manifests in directories created later
+(scaffolding, a copied project) are validated like any other change.`,
+	Example: `  vibe-validator watch .
+  vibe-validator watch . --summary | while read -r line; do notify-send "$line"; done`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		root := args[0]
+
+		cfg, err := loadConfig(root)
+		if err != nil {
+			fmt.Printf("❌ Config failed: %v\n", err)
+			os.Exit(1)
+		}
+		f, err := loadFeeds(nil, func(msg string) { fmt.Println(msg) })
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			fmt.Printf("❌ Watch failed: %v\n", err)
+			os.Exit(1)
+		}
+		defer watcher.Close()
+
+		w := &manifestWatcher{watcher: watcher, cfg: cfg, feeds: f, seen: map[string]bool{}, pending: map[string]*time.Timer{}}
+		manifests, err := w.addTree(root, true)
+		if err != nil {
+			fmt.Printf("❌ Watch failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("👀 Watching %d manifest(s), %d known dependencies, in %s (Ctrl+C to stop)\n", manifests, len(w.seen), root)
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				w.handle(event)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Printf("❌ Watch error: %v\n", err)
+			}
+		}
+	},
+}
+
+// manifestWatcher tracks which dependencies have been seen so only new
+// ones are validated
+type manifestWatcher struct {
+	watcher *fsnotify.Watcher
+	cfg     config.Config
+	feeds   feeds
+
+	mu      sync.Mutex
+	seen    map[string]bool // ecosystem:normalized name
+	pending map[string]*time.Timer
+
+	// validations run concurrently; their output mustn't interleave
+	out sync.Mutex
+}
+
+// addTree watches every directory under root. With seed, the dependencies
+// already declared there are recorded as seen; otherwise, as for a
+// directory created while watching, its manifests are validated.
+func (w *manifestWatcher) addTree(root string, seed bool) (int, error) {
+	manifests := 0
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (info.Name() == ".git" || (!includeVendor && scanner.IsVendorDir(info.Name()))) {
+				return filepath.SkipDir
+			}
+			return w.watcher.Add(path)
+		}
+		if w.wanted(path) {
+			manifests++
+			if seed {
+				w.newDeps(path)
+			} else {
+				w.schedule(path)
+			}
+		}
+		return nil
+	})
+	return manifests, err
+}
+
+func (w *manifestWatcher) wanted(path string) bool {
+	return scanner.IsManifest(path) && (includeLockfiles || !scanner.IsLockfile(path))
+}
+
+// handle reacts to a filesystem event. Editors save in bursts (truncate,
+// write, chmod, or write-and-rename), so each file settles for a moment
+// before it is read.
+func (w *manifestWatcher) handle(event fsnotify.Event) {
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			w.addTree(event.Name, false)
+			return
+		}
+	}
+	if !w.wanted(event.Name) || !(event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename)) {
+		return
+	}
+	w.schedule(event.Name)
+}
+
+// schedule validates a manifest once it has settled
+func (w *manifestWatcher) schedule(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if timer, found := w.pending[path]; found {
+		timer.Stop()
+	}
+	w.pending[path] = time.AfterFunc(300*time.Millisecond, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		w.mu.Unlock()
+		w.validate(path)
+	})
+}
+
+// newDeps re-parses one manifest and returns the dependencies in it that
+// haven't been seen before, marking them seen
+func (w *manifestWatcher) newDeps(path string) (scanner.AllDeps, scanner.AllVersions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	eco, deps, versions, err := scanner.ParseManifest(path, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fresh := scanner.AllDeps{eco: scanner.DepMap{}}
+	freshVersions := scanner.AllVersions{eco: scanner.DepMap{}}
+	for name, paths := range deps {
+		key := eco + ":" + osv.NormalizeName(eco, name)
+		if w.seen[key] {
+			continue
+		}
+		w.seen[key] = true
+		fresh[eco][name] = paths
+		freshVersions[eco][name] = versions[name]
+	}
+	return fresh, freshVersions, nil
+}
+
+func (w *manifestWatcher) validate(path string) {
+	deps, versions, err := w.newDeps(path)
+	if err != nil {
+		// a half-written file; the next save will be picked up
+		if !watchSummary {
+			fmt.Printf("%s %s: %v\n", time.Now().Format("15:04:05"), path, err)
+		}
+		return
+	}
+
+	var names []string
+	for _, pkgs := range deps {
+		for name := range pkgs {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	results := validator.ValidatePackages(deps, versions)
+	applyChecks(results, w.cfg, checkOptions{Repos: checkRepos, Licenses: checkLicenses}, w.feeds, quiet)
+
+	w.out.Lock()
+	defer w.out.Unlock()
+
+	var flagged []string
+	for _, r := range results {
+		if r.Status != "safe" {
+			flagged = append(flagged, fmt.Sprintf("%s (%s)", r.Name, r.Status))
+		}
+	}
+
+	if watchSummary {
+		if len(flagged) == 0 {
+			fmt.Printf("vibe-validator: %s: %d new, all safe\n", path, len(names))
+		} else {
+			fmt.Printf("vibe-validator: %s: %d new, %d need attention: %s\n", path, len(names), len(flagged), strings.Join(flagged, ", "))
+		}
+		return
+	}
+
+	fmt.Printf("%s %s: new %s\n", time.Now().Format("15:04:05"), path, strings.Join(names, ", "))
+	reporter.PrintReport(results, reporter.Options{Verbosity: max(verbosity, 1)})
+}
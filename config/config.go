@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+
+	"github.com/pelletier/go-toml"
+)
+
+// FileName is the config file picked up from the root of a scanned project
+const FileName = ".vibe-validator.toml"
+
+// Config holds the settings read from a vibe-validator config file
+type Config struct {
+	Repository Repository `toml:"repository"`
+	License    License    `toml:"license"`
+	Score      Score      `toml:"score"`
+}
+
+// Repository controls source repository verification
+type Repository struct {
+	// SharedThreshold is how many unrelated packages may claim one
+	// repository before they are all flagged
+	SharedThreshold int     `toml:"shared_threshold"`
+	Forges          []Forge `toml:"forge"`
+}
+
+// License is the license policy. Entries are SPDX identifiers; a trailing
+// "*" matches a prefix and a bare "GPL-3.0" covers both -only and -or-later.
+type License struct {
+	Allow []string `toml:"allow"` // when set, anything else is flagged
+	Deny  []string `toml:"deny"`
+}
+
+// Score tunes the risk score. Each weight is the most points a signal can
+// add to the 0-100 score.
+type Score struct {
+	// Investigate is the score at which an otherwise safe dependency is
+	// flagged for investigation
+	Investigate float64            `toml:"investigate"`
+	Weights     map[string]float64 `toml:"weights"`
+}
+
+// DefaultWeights are the points each risk signal is worth at full strength
+var DefaultWeights = map[string]float64{
+	"existence":         100,
+	"package_age":       30,
+	"version_age":       10,
+	"popularity":        15,
+	"install_scripts":   20,
+	"maintainer_change": 20,
+	"typosquat":         40,
+	"repository":        25,
+	"vulnerabilities":   20,
+}
+
+// Forge describes a code host whose API can confirm a repository exists
+type Forge struct {
+	Host     string `toml:"host"`      // e.g. "github.com"
+	Kind     string `toml:"kind"`      // "github", "gitlab" or "gitea"
+	API      string `toml:"api"`       // API base URL, e.g. "https://api.github.com"
+	TokenEnv string `toml:"token_env"` // environment variable holding an API token
+}
+
+var defaultForges = []Forge{
+	{Host: "github.com", Kind: "github", API: "https://api.github.com", TokenEnv: "GITHUB_TOKEN"},
+	{Host: "gitlab.com", Kind: "gitlab", API: "https://gitlab.com/api/v4", TokenEnv: "GITLAB_TOKEN"},
+	{Host: "codeberg.org", Kind: "gitea", API: "https://codeberg.org/api/v1", TokenEnv: "GITEA_TOKEN"},
+}
+
+// Default returns the settings used when no config file is present
+func Default() Config {
+	var cfg Config
+	cfg.applyDefaults()
+	return cfg
+}
+
+// Load reads a config file, filling anything it leaves out with defaults
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+
+	cfg.applyDefaults()
+	return cfg, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Repository.SharedThreshold == 0 {
+		c.Repository.SharedThreshold = 3
+	}
+
+	if c.Score.Investigate == 0 {
+		c.Score.Investigate = 50
+	}
+	if c.Score.Weights == nil {
+		c.Score.Weights = map[string]float64{}
+	}
+	for signal, weight := range DefaultWeights {
+		if _, set := c.Score.Weights[signal]; !set {
+			c.Score.Weights[signal] = weight
+		}
+	}
+
+	// configured forges win over the built-in entry for the same host
+	for _, def := range defaultForges {
+		found := false
+		for _, f := range c.Repository.Forges {
+			if f.Host == def.Host {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Repository.Forges = append(c.Repository.Forges, def)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package gitscan
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+)
+
+// Index stands for the staging area in place of a revision
+const Index = ""
+
+// Change describes how a dependency differs from the old revision
+type Change struct {
+	Kind string   // "added" or "version_changed"
+	From []string // versions pinned before, for version changes
+}
+
+// Scan parses every manifest under dir as of a revision, or as staged when
+// rev is Index, without touching the working tree, along with what
+// Dockerfiles, workflows, scripts and notebooks install. Paths are relative
+// to the repository root.
+func Scan(dir, rev string, opts scanner.ScanOptions) (scanner.AllDeps, scanner.AllVersions, error) {
+	var files []string
+	var err error
+	if rev == Index {
+		files, err = git(dir, "ls-files", "--cached", "--full-name", "-z", "--", ".")
+	} else {
+		files, err = git(dir, "ls-tree", "-r", "--name-only", "--full-name", "-z", rev, "--", ".")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deps := make(scanner.AllDeps)
+	versions := make(scanner.AllVersions)
+	for _, file := range files {
+		manifest := scanner.IsManifest(file)
+		if !(manifest || scanner.IsInstallSource(file)) ||
+			(!opts.IncludeLockfiles && scanner.IsLockfile(file)) ||
+			(!opts.IncludeVendor && scanner.InVendorDir(file)) {
+			continue
+		}
+
+		data, err := show(dir, rev+":"+file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !manifest {
+			scanner.MergeInstallSource(deps, versions, file, data, opts.ResolveGoModule)
+			continue
+		}
+		if err := scanner.MergeManifest(deps, versions, file, data); err != nil && opts.Verbosity >= 2 {
+			fmt.Printf("Skipping %s: %v\n", file, err)
+		}
+	}
+	return deps, versions, nil
+}
+
+// Diff keeps the dependencies in the new scan that the old one lacked, or
+// that now pin a version the old one didn't. Changes are keyed by
+// ecosystem and normalized name.
+func Diff(oldDeps scanner.AllDeps, oldVersions scanner.AllVersions, newDeps scanner.AllDeps, newVersions scanner.AllVersions) (scanner.AllDeps, scanner.AllVersions, map[string]Change) {
+	deps := make(scanner.AllDeps)
+	versions := make(scanner.AllVersions)
+	changes := map[string]Change{}
+
+	for eco, names := range newDeps {
+		before := map[string]string{}
+		for name := range oldDeps[eco] {
+			before[osv.NormalizeName(eco, name)] = name
+		}
+
+		for name, paths := range names {
+			key := Key(eco, name)
+			oldName, existed := before[osv.NormalizeName(eco, name)]
+
+			var change Change
+			switch {
+			case !existed:
+				change = Change{Kind: "added"}
+			case hasNewVersion(oldVersions[eco][oldName], newVersions[eco][name]):
+				change = Change{Kind: "version_changed", From: oldVersions[eco][oldName]}
+			default:
+				continue
+			}
+
+			if deps[eco] == nil {
+				deps[eco] = make(scanner.DepMap)
+				versions[eco] = make(scanner.DepMap)
+			}
+			deps[eco][name] = paths
+			if v := newVersions[eco][name]; len(v) > 0 {
+				versions[eco][name] = v
+			}
+			changes[key] = change
+		}
+	}
+	return deps, versions, changes
+}
+
+// Key identifies a dependency in the changes returned by Diff
+func Key(eco, name string) string {
+	return eco + ":" + osv.NormalizeName(eco, name)
+}
+
+func hasNewVersion(old, current []string) bool {
+	seen := map[string]bool{}
+	for _, v := range old {
+		seen[v] = true
+	}
+	for _, v := range current {
+		if !seen[v] {
+			return true
+		}
+	}
+	return false
+}
+
+// show reads a file from a revision ("rev:path") or the index (":path")
+func show(dir, object string) ([]byte, error) {
+	cmd := exec.Command("git", "-C", dir, "show", object)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show %s: %s", object, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// git runs a command that lists NUL-separated paths
+func git(dir string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+
+	var paths []string
+	for _, p := range strings.Split(string(out), "\x00") {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
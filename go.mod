@@ -6,9 +6,11 @@ toolchain go1.24.4
 
 require (
 	github.com/briandowns/spinner v1.23.2
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/pelletier/go-toml v1.9.5
 	github.com/spf13/cobra v1.9.1
 	golang.org/x/mod v0.25.0
+	golang.org/x/term v0.1.0
 )
 
 require (
@@ -17,6 +19,5 @@ require (
 	github.com/mattn/go-colorable v0.1.2 // indirect
 	github.com/mattn/go-isatty v0.0.8 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
-	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
-	golang.org/x/term v0.1.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
 )
@@ -0,0 +1,453 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+)
+
+// LSP diagnostic severities
+const (
+	severityError   = 1
+	severityWarning = 2
+)
+
+// Server speaks the Language Server Protocol for dependency manifests,
+// publishing a diagnostic for every dependency that isn't safe
+type Server struct {
+	name    string
+	version string
+	check   func(eco, name, version string) validator.ValidationResult
+	ttl     time.Duration
+
+	out     io.Writer
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	docs    map[string]*document
+	results map[string]cachedResult
+}
+
+type cachedResult struct {
+	result  validator.ValidationResult
+	expires time.Time
+}
+
+// document is one parsed version of an open file. It is never modified once
+// stored, so its text and declarations always agree, and diagnostics running
+// in the background can read it without the lock.
+type document struct {
+	uri     string
+	version int
+	text    string
+	decls   []scanner.Declaration
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+type diagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Code     string   `json:"code,omitempty"`
+	Message  string   `json:"message"`
+}
+
+type textDocumentItem struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+type textDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position position `json:"position"`
+}
+
+// NewServer creates a server; check validates one dependency and is called
+// at most once per package and version every ttl, defaulting to an hour
+func NewServer(name, version string, ttl time.Duration, check func(eco, name, version string) validator.ValidationResult) *Server {
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+	return &Server{
+		name:    name,
+		version: version,
+		check:   check,
+		ttl:     ttl,
+		docs:    map[string]*document{},
+		results: map[string]cachedResult{},
+	}
+}
+
+// Serve handles Content-Length framed JSON-RPC messages until exit or EOF
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.out = w
+	reader := bufio.NewReader(r)
+
+	for {
+		body, err := readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: -32700, Message: "parse error"}})
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, rpcErr := s.handle(req)
+		// notifications carry no id and get no response
+		if len(req.ID) == 0 {
+			continue
+		}
+		s.write(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+	}
+}
+
+func (s *Server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{"openClose": true, "change": 1, "save": true},
+				"hoverProvider":    true,
+			},
+			"serverInfo": map[string]string{"name": s.name, "version": s.version},
+		}, nil
+
+	case "shutdown":
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument textDocumentItem `json:"textDocument"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			s.update(params.TextDocument.URI, params.TextDocument.Version, params.TextDocument.Text)
+		}
+
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument   textDocumentItem `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		// full sync: the last change holds the whole document
+		if err := json.Unmarshal(req.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			s.update(params.TextDocument.URI, params.TextDocument.Version, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument textDocumentItem `json:"textDocument"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			s.mu.Lock()
+			delete(s.docs, params.TextDocument.URI)
+			s.mu.Unlock()
+			s.publish(params.TextDocument.URI, []diagnostic{})
+		}
+
+	case "textDocument/hover":
+		var params textDocumentPosition
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: -32602, Message: err.Error()}
+		}
+		return s.hover(params), nil
+
+	default:
+		if len(req.ID) > 0 {
+			return nil, &rpcError{Code: -32601, Message: "method not found: " + req.Method}
+		}
+	}
+	return nil, nil
+}
+
+// update re-parses a document and validates its dependencies in the
+// background; registry lookups are slow and mustn't hold up the editor
+func (s *Server) update(uri string, version int, text string) {
+	path := uriPath(uri)
+	if !scanner.IsManifest(path) {
+		return
+	}
+
+	decls, err := scanner.Declarations(path, []byte(text))
+	if err != nil {
+		// half-typed JSON doesn't parse; keep the last good document, whose
+		// positions belong to its own text
+		return
+	}
+
+	doc := &document{uri: uri, version: version, text: text, decls: decls}
+	s.mu.Lock()
+	s.docs[uri] = doc
+	s.mu.Unlock()
+
+	go s.diagnose(doc)
+}
+
+func (s *Server) diagnose(doc *document) {
+	diagnostics := []diagnostic{}
+	lines := strings.Split(doc.text, "\n")
+
+	for _, d := range doc.decls {
+		result := s.result(d)
+
+		s.mu.Lock()
+		stale := s.docs[doc.uri] != doc
+		s.mu.Unlock()
+		if stale {
+			return // a newer version is being diagnosed
+		}
+
+		if result.Status == "safe" {
+			continue
+		}
+		severity := severityWarning
+		if result.Status == "not_found" || result.Status == "malicious" {
+			severity = severityError
+		}
+		diagnostics = append(diagnostics, diagnostic{
+			Range:    declRange(lines, d),
+			Severity: severity,
+			Source:   s.name,
+			Code:     result.Status,
+			Message:  diagnosticMessage(result),
+		})
+	}
+
+	s.publish(doc.uri, diagnostics)
+}
+
+// result validates a declaration, reusing verdicts younger than the TTL
+func (s *Server) result(d scanner.Declaration) validator.ValidationResult {
+	if result, found := s.cached(d); found {
+		return result
+	}
+
+	result := s.check(d.Ecosystem, d.Name, d.Version)
+	now := time.Now()
+	s.mu.Lock()
+	// drop expired verdicts so a long session doesn't keep every package
+	// it has ever seen
+	for k, c := range s.results {
+		if now.After(c.expires) {
+			delete(s.results, k)
+		}
+	}
+	s.results[resultKey(d)] = cachedResult{result: result, expires: now.Add(s.ttl)}
+	s.mu.Unlock()
+	return result
+}
+
+// cached returns a declaration's verdict if one is known and fresh
+func (s *Server) cached(d scanner.Declaration) (validator.ValidationResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, found := s.results[resultKey(d)]
+	if !found || time.Now().After(c.expires) {
+		return validator.ValidationResult{}, false
+	}
+	return c.result, true
+}
+
+func resultKey(d scanner.Declaration) string {
+	return d.Ecosystem + ":" + d.Name + "@" + d.Version
+}
+
+// hover answers from the verdicts diagnostics have already fetched, so a
+// registry lookup never holds up the message loop
+func (s *Server) hover(params textDocumentPosition) any {
+	s.mu.Lock()
+	doc, found := s.docs[params.TextDocument.URI]
+	s.mu.Unlock()
+	if !found {
+		return nil
+	}
+
+	lines := strings.Split(doc.text, "\n")
+	for _, d := range doc.decls {
+		r := declRange(lines, d)
+		if params.Position.Line != r.Start.Line || params.Position.Character < r.Start.Character || params.Position.Character > r.End.Character {
+			continue
+		}
+		text := "Still checking " + d.Name + "..."
+		if result, found := s.cached(d); found {
+			text = hoverText(result)
+		}
+		return map[string]any{
+			"contents": map[string]string{"kind": "markdown", "value": text},
+			"range":    r,
+		}
+	}
+	return nil
+}
+
+func (s *Server) publish(uri string, diagnostics []diagnostic) {
+	s.write(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  map[string]any{"uri": uri, "diagnostics": diagnostics},
+	})
+}
+
+func (s *Server) write(msg any) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+// readMessage reads one Content-Length framed message body
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(name, "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("bad Content-Length: %w", err)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("message without Content-Length")
+	}
+
+	body := make([]byte, length)
+	_, err := io.ReadFull(r, body)
+	return body, err
+}
+
+// declRange converts a declaration's byte columns to the UTF-16 offsets
+// LSP positions are measured in
+func declRange(lines []string, d scanner.Declaration) lspRange {
+	line := ""
+	if d.Line < len(lines) {
+		line = lines[d.Line]
+	}
+	return lspRange{
+		Start: position{Line: d.Line, Character: utf16Len(line, d.Column)},
+		End:   position{Line: d.Line, Character: utf16Len(line, d.EndColumn)},
+	}
+}
+
+func utf16Len(line string, byteCol int) int {
+	if byteCol > len(line) {
+		byteCol = len(line)
+	}
+	return len(utf16.Encode([]rune(line[:byteCol])))
+}
+
+func diagnosticMessage(r validator.ValidationResult) string {
+	msg := fmt.Sprintf("%s: %s", r.Status, r.Details)
+	for _, f := range r.Findings {
+		msg += "\n" + f.Details
+	}
+	return msg
+}
+
+func hoverText(r validator.ValidationResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** (%s): %s, risk score %.0f\n\n%s\n", r.Name, r.Source, r.Status, r.Score, r.Details)
+
+	m := r.Metadata
+	if m.LatestVersion != "" {
+		fmt.Fprintf(&b, "\n- Latest: %s", m.LatestVersion)
+		if !m.LatestRelease.IsZero() {
+			fmt.Fprintf(&b, " (%s)", m.LatestRelease.Format("2006-01-02"))
+		}
+	}
+	if !m.Created.IsZero() {
+		fmt.Fprintf(&b, "\n- First published: %s", m.Created.Format("2006-01-02"))
+	}
+	if m.Downloads > 0 {
+		fmt.Fprintf(&b, "\n- Downloads: %d", m.Downloads)
+		if m.DownloadPeriod != "" {
+			fmt.Fprintf(&b, " (%s)", m.DownloadPeriod)
+		}
+	}
+	if r.Repository != "" {
+		fmt.Fprintf(&b, "\n- Repository: %s", r.Repository)
+	}
+	if r.License != "" {
+		fmt.Fprintf(&b, "\n- License: %s", r.License)
+	}
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "\n- ⚠️ %s", f.Details)
+	}
+	return b.String()
+}
+
+// uriPath turns a file:// URI into a path; only the base name matters for
+// picking a parser
+func uriPath(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Path != "" {
+		return filepath.FromSlash(u.Path)
+	}
+	return uri
+}
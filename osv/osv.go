@@ -0,0 +1,434 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Ecosystems maps vibe-validator's ecosystem names to OSV's
+var Ecosystems = map[string]string{
+	"pypi": "PyPI",
+	"npm":  "npm",
+	"go":   "Go",
+	"php":  "Packagist",
+	"ruby": "RubyGems",
+	"rust": "crates.io",
+
+	"actions": "GitHub Actions",
+}
+
+// Entry is the subset of the OSV schema vibe-validator uses
+type Entry struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Withdrawn        string           `json:"withdrawn"`
+	Severity         []severityScore  `json:"severity"`
+	Affected         []Affected       `json:"affected"`
+	DatabaseSpecific databaseSpecific `json:"database_specific"`
+}
+
+// Affected lists the versions of one package an entry applies to
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges           []Range          `json:"ranges"`
+	Versions         []string         `json:"versions"`
+	DatabaseSpecific databaseSpecific `json:"database_specific"`
+}
+
+// Range is a sequence of introduced/fixed events in one version scheme
+type Range struct {
+	Type   string  `json:"type"` // "SEMVER", "ECOSYSTEM" or "GIT"
+	Events []Event `json:"events"`
+}
+
+// Event marks a point where a range starts or stops applying
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type severityScore struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type databaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+// Advisory is an entry that matched a specific dependency version
+type Advisory struct {
+	ID       string
+	Aliases  []string
+	Summary  string
+	Severity string   // "CRITICAL", "HIGH", "MEDIUM", "LOW" or "" when unscored
+	Fixed    []string // versions later than the matched one that fix it
+}
+
+// Malicious reports whether the advisory reports a malicious package (an
+// OSV MAL- entry) rather than a vulnerability
+func (a Advisory) Malicious() bool {
+	return strings.HasPrefix(a.ID, "MAL-")
+}
+
+// DB indexes OSV entries by ecosystem and package name
+type DB struct {
+	entries map[string]map[string][]*Entry
+}
+
+// Load reads every OSV export found under dir: the per-ecosystem all.zip
+// files from osv-vulnerabilities.storage.googleapis.com, or loose .json
+// entries. When wanted is non-nil only those ecosystems and names are kept,
+// which keeps memory sane when loading the full npm export.
+func Load(dir string, wanted map[string]map[string][]string) (*DB, error) {
+	db := &DB{entries: map[string]map[string][]*Entry{}}
+
+	keep := func(eco, name string) bool {
+		return true
+	}
+	if wanted != nil {
+		index := map[string]bool{}
+		for source, names := range wanted {
+			for n := range names {
+				index[Ecosystems[source]+"\x00"+normalizeName(Ecosystems[source], n)] = true
+			}
+		}
+		keep = func(eco, name string) bool {
+			return index[eco+"\x00"+name]
+		}
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".zip":
+			return db.loadZip(path, keep)
+		case ".json":
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return db.add(f, keep)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (db *DB) loadZip(path string, keep func(eco, name string) bool) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = db.add(rc, keep)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) add(r io.Reader, keep func(eco, name string) bool) error {
+	var entry Entry
+	if err := json.NewDecoder(r).Decode(&entry); err != nil {
+		// one malformed entry shouldn't sink the whole database
+		return nil
+	}
+	if entry.Withdrawn != "" {
+		return nil
+	}
+
+	for _, a := range entry.Affected {
+		eco := ecosystemBase(a.Package.Ecosystem)
+		name := normalizeName(eco, a.Package.Name)
+		if !keep(eco, name) {
+			continue
+		}
+		if db.entries[eco] == nil {
+			db.entries[eco] = map[string][]*Entry{}
+		}
+		if !containsEntry(db.entries[eco][name], &entry) {
+			db.entries[eco][name] = append(db.entries[eco][name], &entry)
+		}
+	}
+	return nil
+}
+
+// Entries returns every loaded entry naming the package, whatever the version
+func (db *DB) Entries(source, name string) []*Entry {
+	eco := Ecosystems[source]
+	return db.entries[eco][normalizeName(eco, name)]
+}
+
+// Query returns the advisories affecting one version of a package
+func (db *DB) Query(source, name, version string) []Advisory {
+	eco := Ecosystems[source]
+	name = normalizeName(eco, name)
+
+	var advisories []Advisory
+	for _, entry := range db.entries[eco][name] {
+		matched := false
+		var fixed []string
+		for _, a := range entry.Affected {
+			if ecosystemBase(a.Package.Ecosystem) != eco || normalizeName(eco, a.Package.Name) != name {
+				continue
+			}
+			if ok, fixes := a.match(eco, version); ok {
+				matched = true
+				fixed = append(fixed, fixes...)
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		advisories = append(advisories, Advisory{
+			ID:       entry.ID,
+			Aliases:  entry.Aliases,
+			Summary:  entry.Summary,
+			Severity: entry.severity(),
+			Fixed:    fixed,
+		})
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		return advisories[i].ID < advisories[j].ID
+	})
+	return advisories
+}
+
+// QueryAnyVersion returns the advisories that affect every version of a
+// package, for dependencies declared without a pinned version. Malicious
+// package entries usually have this shape.
+func (db *DB) QueryAnyVersion(source, name string) []Advisory {
+	eco := Ecosystems[source]
+	name = normalizeName(eco, name)
+
+	var advisories []Advisory
+	for _, entry := range db.entries[eco][name] {
+		for _, a := range entry.Affected {
+			if ecosystemBase(a.Package.Ecosystem) == eco && normalizeName(eco, a.Package.Name) == name && a.allVersions() {
+				advisories = append(advisories, Advisory{
+					ID:       entry.ID,
+					Aliases:  entry.Aliases,
+					Summary:  entry.Summary,
+					Severity: entry.severity(),
+				})
+				break
+			}
+		}
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		return advisories[i].ID < advisories[j].ID
+	})
+	return advisories
+}
+
+// NormalizeName applies the registry's name equivalence rules, e.g. PEP 503
+// for PyPI, so names can be compared across manifests and feeds
+func NormalizeName(source, name string) string {
+	return normalizeName(Ecosystems[source], name)
+}
+
+// allVersions reports whether the entry names the package without ever
+// ruling a version out
+func (a Affected) allVersions() bool {
+	if len(a.Ranges) == 0 && len(a.Versions) == 0 {
+		return true
+	}
+	for _, r := range a.Ranges {
+		if r.Type == "GIT" {
+			continue
+		}
+		fromZero, bounded := false, false
+		for _, e := range r.Events {
+			if e.Introduced == "0" {
+				fromZero = true
+			}
+			if e.Fixed != "" || e.LastAffected != "" || e.Limit != "" {
+				bounded = true
+			}
+		}
+		if fromZero && !bounded {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the version is affected and which later versions
+// fix the ranges it falls in. Versions the ecosystem can't order only match
+// when the entry lists them by name.
+func (a Affected) match(eco, version string) (bool, []string) {
+	cmp := comparator(eco)
+	valid := validVersion(eco, version)
+
+	matched := false
+	var fixed []string
+	for _, r := range a.Ranges {
+		if r.Type == "GIT" || !valid || !r.contains(cmp, version) {
+			continue
+		}
+		matched = true
+		for _, e := range r.Events {
+			if e.Fixed != "" && cmp(e.Fixed, version) > 0 {
+				fixed = append(fixed, e.Fixed)
+			}
+		}
+	}
+
+	for _, v := range a.Versions {
+		if v == version || (valid && cmp(v, version) == 0) {
+			matched = true
+		}
+	}
+	return matched, fixed
+}
+
+// contains walks the range's events in version order, toggling whether the
+// version is affected as it passes each introduced and fixed point
+func (r Range) contains(cmp func(a, b string) int, version string) bool {
+	events := append([]Event(nil), r.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareEvents(cmp, events[i], events[j]) < 0
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || cmp(version, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if cmp(version, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if cmp(version, e.LastAffected) > 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			if cmp(version, e.Limit) >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+func compareEvents(cmp func(a, b string) int, a, b Event) int {
+	av, bv := a.version(), b.version()
+	if av == "0" && bv == "0" {
+		return 0
+	}
+	if av == "0" {
+		return -1
+	}
+	if bv == "0" {
+		return 1
+	}
+	return cmp(av, bv)
+}
+
+func (e Event) version() string {
+	for _, v := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// severity prefers the advisory database's own rating and falls back to
+// scoring the CVSS vector
+func (e *Entry) severity() string {
+	if s := normalizeSeverity(e.DatabaseSpecific.Severity); s != "" {
+		return s
+	}
+	for _, a := range e.Affected {
+		if s := normalizeSeverity(a.DatabaseSpecific.Severity); s != "" {
+			return s
+		}
+	}
+	for _, s := range e.Severity {
+		if s.Type == "CVSS_V3" {
+			if score, ok := cvss3BaseScore(s.Score); ok {
+				return cvssRating(score)
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeSeverity(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "MODERATE" {
+		return "MEDIUM"
+	}
+	return s
+}
+
+// ecosystemBase drops OSV's ecosystem suffixes such as "Debian:11"
+func ecosystemBase(eco string) string {
+	base, _, _ := strings.Cut(eco, ":")
+	return base
+}
+
+// normalizeName applies each registry's own name equivalence rules
+func normalizeName(eco, name string) string {
+	switch eco {
+	case "PyPI":
+		// PEP 503
+		name = strings.ToLower(name)
+		return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		}), "-")
+	case "Packagist", "GitHub Actions":
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+func containsEntry(entries []*Entry, e *Entry) bool {
+	for _, existing := range entries {
+		if existing == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package osv
+
+import "testing"
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		name    string
+		eco     string
+		events  []Event
+		version string
+		want    bool
+	}{
+		{"before fix", "npm", []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "1.1.9", true},
+		{"at fix", "npm", []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "1.2.0", false},
+		{"before introduced", "npm", []Event{{Introduced: "1.0.0"}, {Fixed: "1.2.0"}}, "0.9.0", false},
+		{"at introduced", "npm", []Event{{Introduced: "1.0.0"}, {Fixed: "1.2.0"}}, "1.0.0", true},
+		{"prerelease of introduced", "npm", []Event{{Introduced: "1.0.0"}}, "1.0.0-rc.1", false},
+		{"prerelease of fix", "npm", []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "1.2.0-beta.1", true},
+		{"never fixed", "npm", []Event{{Introduced: "3.0.0"}}, "9.9.9", true},
+		{"at last affected", "npm", []Event{{Introduced: "1.0.0"}, {LastAffected: "1.1.0"}}, "1.1.0", true},
+		{"after last affected", "npm", []Event{{Introduced: "1.0.0"}, {LastAffected: "1.1.0"}}, "1.1.1", false},
+		{"under limit", "npm", []Event{{Introduced: "0"}, {Limit: "2.0.0"}}, "1.9.9", true},
+		{"at limit", "npm", []Event{{Introduced: "0"}, {Limit: "2.0.0"}}, "2.0.0", false},
+		{"between ranges", "npm", []Event{{Introduced: "0"}, {Fixed: "1.0.0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}, "1.5.0", false},
+		{"second range", "npm", []Event{{Introduced: "0"}, {Fixed: "1.0.0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}, "2.0.5", true},
+		{"unsorted events", "npm", []Event{{Fixed: "2.1.0"}, {Introduced: "2.0.0"}, {Fixed: "1.0.0"}, {Introduced: "0"}}, "2.0.5", true},
+		{"pep440 prerelease of fix", "PyPI", []Event{{Introduced: "0"}, {Fixed: "2.0"}}, "2.0rc1", true},
+		{"pep440 post release of fix", "PyPI", []Event{{Introduced: "0"}, {Fixed: "2.0"}}, "2.0.post1", false},
+		{"pep440 dev release of introduced", "PyPI", []Event{{Introduced: "1.5"}, {Fixed: "2.0"}}, "1.5.dev0", false},
+		{"pep440 epoch", "PyPI", []Event{{Introduced: "0"}, {Fixed: "2.0"}}, "1!1.0", false},
+		{"gem prerelease of fix", "RubyGems", []Event{{Introduced: "0"}, {Fixed: "6.1.0"}}, "6.1.0.rc1", true},
+		{"gem segment ordering", "RubyGems", []Event{{Introduced: "1.9"}, {Fixed: "1.10"}}, "1.9.5", true},
+	}
+	for _, tt := range tests {
+		r := Range{Type: "ECOSYSTEM", Events: tt.events}
+		if got := r.contains(comparator(tt.eco), tt.version); got != tt.want {
+			t.Errorf("%s: contains(%q) = %v, want %v", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
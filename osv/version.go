@@ -0,0 +1,303 @@
+package osv
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/mod/semver"
+)
+
+// comparator returns the version ordering used by an OSV ecosystem
+func comparator(eco string) func(a, b string) int {
+	switch eco {
+	case "PyPI":
+		return comparePEP440
+	case "RubyGems", "Packagist":
+		return compareGem
+	default:
+		// npm, crates.io and Go all follow semver; go.mod's "v" prefix is
+		// optional in OSV so both sides are normalized to carry it
+		return compareSemver
+	}
+}
+
+var (
+	// pep440Pattern is PEP 440's own version pattern
+	pep440Pattern = regexp.MustCompile(`(?i)^v?(\d+!)?\d+(\.\d+)*([-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?\d*)?(-\d+|[-_.]?(post|rev|r)[-_.]?\d*)?([-_.]?dev[-_.]?\d*)?(\+[a-z0-9]+([-_.][a-z0-9]+)*)?$`)
+	// gemPattern is Gem::Version's, which Composer's numbered versions fit
+	gemPattern = regexp.MustCompile(`^v?\d+(\.[0-9a-zA-Z]+)*(-[0-9a-zA-Z.-]+)?$`)
+)
+
+// validVersion reports whether a version can be ordered in an ecosystem.
+// Others, like Composer's dev-master or a git commit, can't be placed in a
+// range at all.
+func validVersion(eco, version string) bool {
+	version = strings.TrimSpace(version)
+	switch eco {
+	case "PyPI":
+		return pep440Pattern.MatchString(version)
+	case "RubyGems":
+		return gemPattern.MatchString(version)
+	case "Packagist":
+		// dev-main and 1.x-dev name branches, not releases
+		lower := strings.ToLower(version)
+		return gemPattern.MatchString(version) && !strings.HasPrefix(lower, "dev-") && !strings.HasSuffix(lower, "-dev")
+	case "GitHub Actions":
+		// refs are commits and branches as often as tags, and a major tag
+		// like v4 moves with each release, so only full release tags match
+		v := "v" + strings.TrimPrefix(version, "v")
+		return semver.IsValid(v) && semver.Canonical(v) == v
+	default:
+		return semver.IsValid("v" + strings.TrimPrefix(version, "v"))
+	}
+}
+
+func compareSemver(a, b string) int {
+	return semver.Compare("v"+strings.TrimPrefix(a, "v"), "v"+strings.TrimPrefix(b, "v"))
+}
+
+// compareGem follows Gem::Version: dot separated segments where numbers
+// compare numerically and any letters mark a prerelease that sorts before
+// the plain release. Composer versions are close enough to share it.
+func compareGem(a, b string) int {
+	as, bs := gemSegments(a), gemSegments(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		} else {
+			x = "0"
+		}
+		if i < len(bs) {
+			y = bs[i]
+		} else {
+			y = "0"
+		}
+		if c := compareSegment(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func gemSegments(v string) []string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v = strings.ReplaceAll(v, "-", ".pre.")
+
+	var segments []string
+	for _, part := range strings.Split(v, ".") {
+		// split "0rc1" into "0", "rc", "1"
+		start := 0
+		for i := 1; i <= len(part); i++ {
+			if i == len(part) || unicode.IsDigit(rune(part[i])) != unicode.IsDigit(rune(part[i-1])) {
+				if part[start:i] != "" {
+					segments = append(segments, part[start:i])
+				}
+				start = i
+			}
+		}
+	}
+	// trailing zeros don't change a gem version: 1.0.0 == 1
+	for len(segments) > 1 && isZero(segments[len(segments)-1]) {
+		segments = segments[:len(segments)-1]
+	}
+	return segments
+}
+
+func compareSegment(x, y string) int {
+	xNum, yNum := isNumeric(x), isNumeric(y)
+	switch {
+	case xNum && yNum:
+		return compareNumeric(x, y)
+	case xNum:
+		return 1
+	case yNum:
+		return -1
+	default:
+		return strings.Compare(x, y)
+	}
+}
+
+// comparePEP440 orders Python versions: epoch, release, then
+// dev < pre < final < post
+func comparePEP440(a, b string) int {
+	x, y := parsePEP440(a), parsePEP440(b)
+
+	if c := compareNumeric(x.epoch, y.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(x.release) || i < len(y.release); i++ {
+		xr, yr := "0", "0"
+		if i < len(x.release) {
+			xr = x.release[i]
+		}
+		if i < len(y.release) {
+			yr = y.release[i]
+		}
+		if c := compareNumeric(xr, yr); c != 0 {
+			return c
+		}
+	}
+
+	if c := compareInts(x.preRank(), y.preRank()); c != 0 {
+		return c
+	}
+	if x.pre != "" && y.pre != "" {
+		if c := compareNumeric(x.preNum, y.preNum); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(boolRank(x.post != ""), boolRank(y.post != "")); c != 0 {
+		return c
+	}
+	if c := compareNumeric(orZero(x.post), orZero(y.post)); c != 0 {
+		return c
+	}
+	// a dev release comes before the release it leads up to
+	if c := compareInts(boolRank(x.dev == ""), boolRank(y.dev == "")); c != 0 {
+		return c
+	}
+	return compareNumeric(orZero(x.dev), orZero(y.dev))
+}
+
+type pep440 struct {
+	epoch   string
+	release []string
+	pre     string // "a", "b" or "rc"
+	preNum  string
+	post    string
+	dev     string
+}
+
+// preRank places dev-only releases before prereleases, and prereleases
+// before the final release
+func (v pep440) preRank() int {
+	switch {
+	case v.pre == "" && v.post == "" && v.dev != "":
+		return 0
+	case v.pre == "a":
+		return 1
+	case v.pre == "b":
+		return 2
+	case v.pre == "rc":
+		return 3
+	default:
+		return 4
+	}
+}
+
+func parsePEP440(s string) pep440 {
+	v := pep440{epoch: "0"}
+
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.Index(s, "+"); i != -1 {
+		s = s[:i] // local versions don't take part in ordering against public ones
+	}
+	if i := strings.Index(s, "!"); i != -1 {
+		v.epoch, s = s[:i], s[i+1:]
+	}
+
+	i := 0
+	for i < len(s) {
+		j := i
+		for j < len(s) && unicode.IsDigit(rune(s[j])) {
+			j++
+		}
+		if j == i {
+			break
+		}
+		v.release = append(v.release, s[i:j])
+		i = j
+		if i < len(s) && s[i] == '.' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1])) {
+			i++
+			continue
+		}
+		break
+	}
+
+	rest := s[i:]
+	for rest != "" {
+		rest = strings.TrimLeft(rest, ".-_")
+		n := 0
+		for n < len(rest) && unicode.IsLetter(rune(rest[n])) {
+			n++
+		}
+		label := rest[:n]
+		rest = rest[n:]
+		rest = strings.TrimLeft(rest, ".-_")
+		m := 0
+		for m < len(rest) && unicode.IsDigit(rune(rest[m])) {
+			m++
+		}
+		num := rest[:m]
+		rest = rest[m:]
+		if num == "" {
+			num = "0"
+		}
+
+		switch label {
+		case "a", "alpha":
+			v.pre, v.preNum = "a", num
+		case "b", "beta":
+			v.pre, v.preNum = "b", num
+		case "rc", "c", "pre", "preview":
+			v.pre, v.preNum = "rc", num
+		case "post", "rev", "r":
+			v.post = num
+		case "dev":
+			v.dev = num
+		case "":
+			if m == 0 {
+				return v // nothing we understand left
+			}
+			v.post = num // implicit post release, e.g. 1.0-1
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+func compareNumeric(a, b string) int {
+	x, okx := new(big.Int).SetString(a, 10)
+	y, oky := new(big.Int).SetString(b, 10)
+	if !okx || !oky {
+		return strings.Compare(a, b)
+	}
+	return x.Cmp(y)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func isNumeric(s string) bool {
+	return s != "" && strings.TrimFunc(s, unicode.IsDigit) == ""
+}
+
+func isZero(s string) bool {
+	return isNumeric(s) && strings.Trim(s, "0") == ""
+}
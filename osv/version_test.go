@@ -0,0 +1,152 @@
+package osv
+
+import "testing"
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.9.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.11", 1},
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"v0.0.0-20230101000000-abcdef123456", "v0.1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareSemver(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareSemver(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCompareGem(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.0", "1.0.0", 0},
+		{"1", "1.0.0.0", 0},
+		{"v2.0.0", "2.0.0", 0},
+		{"1.9", "1.10", -1},
+		{"1.0.0.1", "1.0.0", 1},
+		// letters mark a prerelease, sorting before the release
+		{"1.0.0.pre", "1.0.0", -1},
+		{"1.0.a", "1.0", -1},
+		{"1.0.0.rc1", "1.0.0", -1},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"2.0.0.rc1", "1.9.9", 1},
+		// prerelease labels compare as strings, numbers numerically
+		{"1.0.0.alpha", "1.0.0.beta", -1},
+		{"1.0.0.beta2", "1.0.0.rc1", -1},
+		{"1.0.0.rc2", "1.0.0.rc10", -1},
+		{"1.0.0.rc1", "1.0.0.rc.1", 0},
+		// a number outranks letters in the same position
+		{"1.0.1", "1.0.a", 1},
+	}
+	for _, tt := range tests {
+		if got := compareGem(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareGem(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareGem(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareGem(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestComparePEP440(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"v1.0", "1.0", 0},
+		{"1.9", "1.10", -1},
+		{"2.0", "1.99.99", 1},
+		// dev < pre < final < post
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0a1", "1.0b1", -1},
+		{"1.0b1", "1.0rc1", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0", "1.0.post1", -1},
+		{"1.0a1", "1.0a2", -1},
+		{"1.0.dev2", "1.0.dev10", -1},
+		{"1.0a1.dev1", "1.0a1", -1},
+		{"1.0.post1.dev1", "1.0.post1", -1},
+		{"1.0.post1.dev1", "1.0", 1},
+		{"1.0.post1", "1.1.dev0", -1},
+		// alternative spellings
+		{"1.0alpha1", "1.0a1", 0},
+		{"1.0c1", "1.0rc1", 0},
+		{"1.0-rc.1", "1.0rc1", 0},
+		{"1.0-1", "1.0.post1", 0},
+		{"1.0.rev2", "1.0.post2", 0},
+		{"1.0RC1", "1.0rc1", 0},
+		// epochs outrank the release
+		{"1!0.1", "2.0", 1},
+		{"1!1.0", "2!0.1", -1},
+		{"0!1.0", "1.0", 0},
+		// local versions don't order against public ones
+		{"1.0+local.1", "1.0", 0},
+		{"1.0+abc", "1.0.post1", -1},
+	}
+	for _, tt := range tests {
+		if got := comparePEP440(tt.a, tt.b); got != tt.want {
+			t.Errorf("comparePEP440(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := comparePEP440(tt.b, tt.a); got != -tt.want {
+			t.Errorf("comparePEP440(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestValidVersion(t *testing.T) {
+	tests := []struct {
+		eco, version string
+		want         bool
+	}{
+		{"PyPI", "1.0", true},
+		{"PyPI", "1!2.0", true},
+		{"PyPI", "1.0rc1", true},
+		{"PyPI", "1.0.post1", true},
+		{"PyPI", "1.0.dev3", true},
+		{"PyPI", "1.0+ubuntu.1", true},
+		{"PyPI", "latest", false},
+		{"PyPI", "1.0-foo", false},
+		{"PyPI", "", false},
+		{"RubyGems", "1.2.3", true},
+		{"RubyGems", "1.0.0.pre", true},
+		{"RubyGems", "1.0.0-rc1", true},
+		{"RubyGems", "master", false},
+		{"Packagist", "1.2.3", true},
+		{"Packagist", "v2.0.0", true},
+		{"Packagist", "dev-main", false},
+		{"Packagist", "1.x-dev", false},
+		{"GitHub Actions", "v4.1.2", true},
+		{"GitHub Actions", "4.1.2", true},
+		{"GitHub Actions", "v4", false},
+		{"GitHub Actions", "v4.1", false},
+		{"GitHub Actions", "main", false},
+		{"GitHub Actions", "b4ffde65f46336ab88eb53be808477a3936bae11", false},
+		{"npm", "1.2.3", true},
+		{"npm", "1.2.3-beta.1", true},
+		{"npm", "^1.2.3", false},
+		{"npm", "latest", false},
+		{"Go", "v1.2.3", true},
+		{"Go", "v0.0.0-20230101000000-abcdef123456", true},
+		{"crates.io", "0.4.x", false},
+	}
+	for _, tt := range tests {
+		if got := validVersion(tt.eco, tt.version); got != tt.want {
+			t.Errorf("validVersion(%q, %q) = %v, want %v", tt.eco, tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,464 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+)
+
+// Options configures the registries a Proxy fronts and the policy it applies
+type Options struct {
+	NPMRegistry string // e.g. https://registry.npmjs.org
+	PyPIIndex   string // e.g. https://pypi.org, serving /simple and /pypi/<name>/json
+
+	// Check validates a package; anything not "safe" is blocked
+	Check func(eco, name string) validator.ValidationResult
+	// BlockedVersion reports why a single release must not be served, e.g.
+	// a malicious version of an otherwise fine package
+	BlockedVersion func(eco, name, version string) (string, bool)
+
+	CacheTTL time.Duration
+	Log      io.Writer
+}
+
+// Proxy is an http.Handler serving npm under /npm/ and PyPI under /pypi/
+type Proxy struct {
+	opts   Options
+	client *http.Client
+
+	mu       sync.Mutex
+	verdicts map[string]verdict
+}
+
+type verdict struct {
+	result  validator.ValidationResult
+	expires time.Time
+}
+
+// New returns a Proxy, defaulting the cache TTL to an hour
+func New(opts Options) *Proxy {
+	if opts.CacheTTL == 0 {
+		opts.CacheTTL = time.Hour
+	}
+	if opts.Log == nil {
+		opts.Log = io.Discard
+	}
+	opts.NPMRegistry = strings.TrimSuffix(opts.NPMRegistry, "/")
+	opts.PyPIIndex = strings.TrimSuffix(opts.PyPIIndex, "/")
+
+	return &Proxy{
+		opts:     opts,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		verdicts: map[string]verdict{},
+	}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "read-only registry proxy", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/npm/"):
+		p.serveNPM(w, r, strings.TrimPrefix(r.URL.Path, "/npm/"))
+	case strings.HasPrefix(r.URL.Path, "/pypi/"):
+		p.servePyPI(w, r, strings.TrimPrefix(r.URL.Path, "/pypi"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveNPM handles packuments (/name, /@scope/name), single versions
+// (/name/1.0.0) and tarballs (/name/-/name-1.0.0.tgz)
+func (p *Proxy) serveNPM(w http.ResponseWriter, r *http.Request, rest string) {
+	upstream := p.opts.NPMRegistry + "/" + rest
+
+	// search, audit and login endpoints don't name a package
+	if rest == "" || strings.HasPrefix(rest, "-/") {
+		p.passThrough(w, r, upstream)
+		return
+	}
+
+	segments := strings.Split(rest, "/")
+	nameLen := 1
+	if strings.HasPrefix(rest, "@") && len(segments) > 1 {
+		nameLen = 2
+	}
+	name := strings.Join(segments[:min(nameLen, len(segments))], "/")
+	tail := segments[min(nameLen, len(segments)):]
+
+	if !p.allowed(w, r, "npm", name) {
+		return
+	}
+
+	switch {
+	case len(tail) == 0:
+		p.rewrite(w, r, upstream, func(body []byte) ([]byte, error) {
+			return p.filterPackument(r, "npm", name, body)
+		})
+	case len(tail) == 2 && tail[0] == "-":
+		base := path.Base(name)
+		version := strings.TrimSuffix(strings.TrimPrefix(tail[1], base+"-"), ".tgz")
+		if p.versionAllowed(w, "npm", name, version) {
+			p.passThrough(w, r, upstream)
+		}
+	case len(tail) == 1 && !strings.HasPrefix(tail[0], "-"):
+		if p.versionAllowed(w, "npm", name, tail[0]) {
+			p.rewrite(w, r, upstream, func(body []byte) ([]byte, error) {
+				return p.rewriteTarballs(r, body), nil
+			})
+		}
+	default:
+		p.passThrough(w, r, upstream)
+	}
+}
+
+// filterPackument drops blocked releases from an npm packument and points
+// tarball URLs back at the proxy
+func (p *Proxy) filterPackument(r *http.Request, eco, name string, body []byte) ([]byte, error) {
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	versions, _ := doc["versions"].(map[string]any)
+	removed := map[string]bool{}
+	for version := range versions {
+		if reason, blocked := p.blockedVersion(eco, name, version); blocked {
+			delete(versions, version)
+			removed[version] = true
+			fmt.Fprintf(p.opts.Log, "stripped %s:%s@%s (%s)\n", eco, name, version, reason)
+		}
+	}
+	if len(removed) > 0 {
+		times, _ := doc["time"].(map[string]any)
+		for version := range removed {
+			delete(times, version)
+		}
+		if tags, ok := doc["dist-tags"].(map[string]any); ok {
+			for tag, v := range tags {
+				if s, _ := v.(string); removed[s] {
+					delete(tags, tag)
+				}
+			}
+			// npm needs a latest tag, so fall back to the newest survivor
+			if _, found := tags["latest"]; !found {
+				if newest := newestVersion(versions, times); newest != "" {
+					tags["latest"] = newest
+				}
+			}
+		}
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return p.rewriteTarballs(r, out), nil
+}
+
+// newestVersion picks the most recently published of the remaining versions
+func newestVersion(versions, times map[string]any) string {
+	newest, newestTime := "", ""
+	for version := range versions {
+		// publish times are RFC 3339, so they sort as strings
+		if t, _ := times[version].(string); t > newestTime || newest == "" {
+			newest, newestTime = version, t
+		}
+	}
+	return newest
+}
+
+// rewriteTarballs sends tarball downloads through the proxy too, so a
+// lockfile pointing at a blocked release can't sidestep the metadata check
+func (p *Proxy) rewriteTarballs(r *http.Request, body []byte) []byte {
+	return bytes.ReplaceAll(body, []byte(p.opts.NPMRegistry+"/"), []byte(proxyBase(r)+"/npm/"))
+}
+
+// servePyPI handles the simple index (/simple/name/) and the JSON API
+// (/pypi/name/json, /pypi/name/version/json)
+func (p *Proxy) servePyPI(w http.ResponseWriter, r *http.Request, rest string) {
+	upstream := p.opts.PyPIIndex + rest
+	segments := strings.Split(strings.Trim(rest, "/"), "/")
+
+	switch {
+	case len(segments) == 2 && segments[0] == "simple":
+		name := segments[1]
+		if !p.allowed(w, r, "pypi", name) {
+			return
+		}
+		p.rewrite(w, r, upstream, func(body []byte) ([]byte, error) {
+			return p.filterSimple(name, body), nil
+		})
+	case len(segments) >= 3 && segments[0] == "pypi" && segments[len(segments)-1] == "json":
+		name := segments[1]
+		if !p.allowed(w, r, "pypi", name) {
+			return
+		}
+		if len(segments) == 4 {
+			if p.versionAllowed(w, "pypi", name, segments[2]) {
+				p.passThrough(w, r, upstream)
+			}
+			return
+		}
+		p.rewrite(w, r, upstream, func(body []byte) ([]byte, error) {
+			return p.filterReleases(name, body)
+		})
+	default:
+		p.passThrough(w, r, upstream)
+	}
+}
+
+var simpleLink = regexp.MustCompile(`(?i)<a [^>]*>([^<]+)</a>`)
+
+// filterSimple drops links to blocked releases from a PEP 503 page or its
+// PEP 691 JSON form
+func (p *Proxy) filterSimple(name string, body []byte) []byte {
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		var doc map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return body
+		}
+		files, _ := doc["files"].([]any)
+		kept := files[:0]
+		for _, f := range files {
+			file, _ := f.(map[string]any)
+			filename, _ := file["filename"].(string)
+			if !p.fileBlocked(name, filename) {
+				kept = append(kept, f)
+			}
+		}
+		doc["files"] = kept
+		out, err := json.Marshal(doc)
+		if err != nil {
+			return body
+		}
+		return out
+	}
+
+	return simpleLink.ReplaceAllFunc(body, func(link []byte) []byte {
+		filename := string(simpleLink.FindSubmatch(link)[1])
+		if p.fileBlocked(name, filename) {
+			return nil
+		}
+		return link
+	})
+}
+
+// filterReleases drops blocked releases from a PyPI JSON API document
+func (p *Proxy) filterReleases(name string, body []byte) ([]byte, error) {
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	releases, _ := doc["releases"].(map[string]any)
+	for version := range releases {
+		if reason, blocked := p.blockedVersion("pypi", name, version); blocked {
+			delete(releases, version)
+			fmt.Fprintf(p.opts.Log, "stripped pypi:%s@%s (%s)\n", name, version, reason)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+func (p *Proxy) fileBlocked(name, filename string) bool {
+	version := distVersion(filename)
+	if version == "" {
+		return false
+	}
+	reason, blocked := p.blockedVersion("pypi", name, version)
+	if blocked {
+		fmt.Fprintf(p.opts.Log, "stripped pypi:%s@%s (%s)\n", name, version, reason)
+	}
+	return blocked
+}
+
+// distVersion reads the version out of a wheel or sdist filename, e.g.
+// requests-2.31.0-py3-none-any.whl or requests-2.31.0.tar.gz
+func distVersion(filename string) string {
+	if strings.HasSuffix(filename, ".whl") {
+		parts := strings.Split(filename, "-")
+		if len(parts) >= 5 {
+			return parts[1]
+		}
+		return ""
+	}
+	for _, ext := range []string{".tar.gz", ".tar.bz2", ".tgz", ".zip", ".egg"} {
+		if base, found := strings.CutSuffix(filename, ext); found {
+			if idx := strings.LastIndex(base, "-"); idx != -1 {
+				return base[idx+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// allowed validates the package and answers with a 403 when policy blocks it
+func (p *Proxy) allowed(w http.ResponseWriter, r *http.Request, eco, name string) bool {
+	result := p.check(eco, name)
+	if result.Status == "safe" {
+		return true
+	}
+
+	fmt.Fprintf(p.opts.Log, "blocked %s:%s [%s] %s\n", eco, name, result.Status, result.Details)
+	deny(w, fmt.Sprintf("vibe-validator blocked %s:%s (%s: %s)", eco, name, result.Status, result.Details))
+	return false
+}
+
+func (p *Proxy) versionAllowed(w http.ResponseWriter, eco, name, version string) bool {
+	reason, blocked := p.blockedVersion(eco, name, version)
+	if !blocked {
+		return true
+	}
+
+	fmt.Fprintf(p.opts.Log, "blocked %s:%s@%s (%s)\n", eco, name, version, reason)
+	deny(w, fmt.Sprintf("vibe-validator blocked %s:%s@%s (%s)", eco, name, version, reason))
+	return false
+}
+
+func (p *Proxy) blockedVersion(eco, name, version string) (string, bool) {
+	if p.opts.BlockedVersion == nil {
+		return "", false
+	}
+	return p.opts.BlockedVersion(eco, name, version)
+}
+
+// check validates a package, remembering the verdict for CacheTTL so an
+// install touching the same package repeatedly only pays for it once
+func (p *Proxy) check(eco, name string) validator.ValidationResult {
+	key := eco + ":" + osv.NormalizeName(eco, name)
+
+	p.mu.Lock()
+	cached, found := p.verdicts[key]
+	p.mu.Unlock()
+	if found && time.Now().Before(cached.expires) {
+		return cached.result
+	}
+
+	result := p.opts.Check(eco, name)
+	if result.Unreachable {
+		// the registry didn't answer; ask again next time
+		return result
+	}
+
+	p.mu.Lock()
+	p.verdicts[key] = verdict{result: result, expires: time.Now().Add(p.opts.CacheTTL)}
+	p.mu.Unlock()
+	return result
+}
+
+// deny answers in a shape both npm and pip print: npm shows the JSON
+// error, pip the status line and body
+func deny(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// passThrough streams the upstream response unchanged
+func (p *Proxy) passThrough(w http.ResponseWriter, r *http.Request, upstream string) {
+	resp, err := p.fetch(r, upstream)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	copyHeaders(w.Header(), resp.Header)
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// rewrite fetches the upstream document and passes successful responses
+// through edit before sending them on. HEAD responses have no body to
+// edit, so they pass straight through.
+func (p *Proxy) rewrite(w http.ResponseWriter, r *http.Request, upstream string, edit func([]byte) ([]byte, error)) {
+	if r.Method == http.MethodHead {
+		p.passThrough(w, r, upstream)
+		return
+	}
+
+	resp, err := p.fetch(r, upstream)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if resp.StatusCode == 200 {
+		if body, err = edit(body); err != nil {
+			http.Error(w, fmt.Sprintf("unreadable upstream response: %v", err), http.StatusBadGateway)
+			return
+		}
+	}
+
+	copyHeaders(w.Header(), resp.Header)
+	// the body no longer matches the upstream's validators
+	w.Header().Del("ETag")
+	w.Header().Del("Last-Modified")
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+}
+
+func (p *Proxy) fetch(r *http.Request, upstream string) (*http.Response, error) {
+	if r.URL.RawQuery != "" {
+		upstream += "?" + r.URL.RawQuery
+	}
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, upstream, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Accept picks npm's abbreviated metadata and PyPI's JSON simple API;
+	// Accept-Encoding is left to the transport so bodies can be rewritten
+	for _, h := range []string{"Accept", "Authorization", "User-Agent", "Npm-Command", "Npm-Session"} {
+		if v := r.Header.Get(h); v != "" {
+			req.Header.Set(h, v)
+		}
+	}
+	return p.client.Do(req)
+}
+
+func copyHeaders(dst, src http.Header) {
+	for k, values := range src {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding", "Connection":
+			continue
+		}
+		for _, v := range values {
+			dst.Add(k, v)
+		}
+	}
+}
+
+// proxyBase is the URL clients reached the proxy on
+func proxyBase(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,20 @@
+package reporter
+
+import (
+	"fmt"
+
+	"github.com/Kelcode-Dev/vibe-validator/baseline"
+)
+
+// PrintResolved lists baseline entries that no longer occur, so they can
+// be pruned by recreating the baseline
+func PrintResolved(entries []baseline.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Printf("\n✅ %d baselined finding(s) resolved; run `vibe-validator baseline create` to prune them:\n", len(entries))
+	for _, e := range entries {
+		fmt.Printf("  %s:%s (%s)\n", e.Ecosystem, e.Name, e.Type)
+	}
+}
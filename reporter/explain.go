@@ -0,0 +1,143 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/utils"
+	"github.com/Kelcode-Dev/vibe-validator/validator"
+)
+
+// PrintExplanation prints everything known about one dependency: registry
+// facts, where it is declared, findings, the score breakdown against the
+// configured weights, and a verdict
+func PrintExplanation(r validator.ValidationResult, cfg config.Score) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	meta := r.Metadata
+
+	fmt.Fprintln(w, "Registry")
+	fmt.Fprintf(w, "  Status:\t%s\n", r.Status)
+	fmt.Fprintf(w, "  Details:\t%s\n", orDash(r.Details))
+	fmt.Fprintf(w, "  First published:\t%s\n", formatTime(meta.Created))
+	fmt.Fprintf(w, "  Latest release:\t%s\n", strings.TrimSpace(meta.LatestVersion+" "+formatTime(meta.LatestRelease)))
+	if meta.DownloadPeriod != "" {
+		fmt.Fprintf(w, "  Downloads:\t%d (%s)\n", meta.Downloads, meta.DownloadPeriod)
+	} else {
+		fmt.Fprintf(w, "  Downloads:\tnot published by this registry\n")
+	}
+	fmt.Fprintf(w, "  Install scripts:\t%s\n", orDash(strings.Join(meta.InstallScripts, ", ")))
+	if meta.NewPublisher != "" {
+		fmt.Fprintf(w, "  New publisher:\t%s\n", meta.NewPublisher)
+	}
+	fmt.Fprintf(w, "  Repository:\t%s\n", orDash(r.Repository))
+	fmt.Fprintf(w, "  License:\t%s\n", orDash(r.License))
+	w.Flush()
+	fmt.Println()
+
+	fmt.Println("Declared in")
+	if len(r.Paths) == 0 {
+		fmt.Println("  (not declared in the scanned tree)")
+	}
+	for _, p := range r.Paths {
+		fmt.Printf("  %s\n", p)
+	}
+	if len(r.Versions) > 0 {
+		fmt.Printf("  Versions: %s\n", strings.Join(r.Versions, ", "))
+	}
+	fmt.Println()
+
+	if len(r.Findings) > 0 {
+		fmt.Println("Findings")
+		for _, f := range r.Findings {
+			fmt.Printf("  [%s] %s\n", f.Type, f.Details)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Risk score: %.0f/100\n", r.Score)
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Signal\tPoints\tWeight\tObserved")
+	seen := map[string]bool{}
+	for _, s := range r.Signals {
+		seen[s.Name] = true
+		fmt.Fprintf(w, "  %s\t%.1f\t%.0f\t%s\n", s.Name, s.Points, s.Weight, s.Value)
+	}
+	var missing []string
+	for name := range cfg.Weights {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		fmt.Fprintf(w, "  %s\t-\t%.0f\t%s\n", name, cfg.Weights[name], "nothing to report")
+	}
+	w.Flush()
+	fmt.Println()
+
+	fmt.Println("Thresholds")
+	fmt.Printf("  Safe dependencies scoring %.0f or more are flagged for investigation\n", cfg.Investigate)
+	fmt.Printf("  Packages first published less than %.0f days ago are flagged as new\n", validator.NewPackageAge.Hours()/24)
+	fmt.Printf("  Names one edit from a popular package (%d+ characters), or two edits (%d+), count as typosquats\n", validator.OneEditMinLength, validator.TwoEditMinLength)
+	fmt.Println()
+
+	fmt.Println("Verdict")
+	fmt.Printf("  %s\n", Verdict(r, cfg))
+}
+
+// Verdict sums up a result in a sentence or two of plain language
+func Verdict(r validator.ValidationResult, cfg config.Score) string {
+	switch {
+	case r.Status == "malicious":
+		ref := ""
+		for _, f := range r.Findings {
+			if f.Type == "malicious" && f.Reference != "" {
+				ref = " (" + f.Reference + ")"
+				break
+			}
+		}
+		return fmt.Sprintf("Do not install. %s is listed as a malicious package%s.", r.Name, ref)
+
+	case r.Status == "not_found":
+		return fmt.Sprintf("%s does not exist in the %s registry. If an assistant suggested it, the name is probably hallucinated, and anyone could register it with malicious code.", r.Name, r.Source)
+
+	case r.Status == "investigate" || r.Score >= cfg.Investigate:
+		var reasons []string
+		for _, s := range r.Signals {
+			if s.Points > 0 && len(reasons) < 3 {
+				reasons = append(reasons, s.Value)
+			}
+		}
+		for _, f := range r.Findings {
+			if len(reasons) == 0 && !strings.HasPrefix(f.Type, "license_") {
+				reasons = append(reasons, f.Details)
+			}
+		}
+		if len(reasons) == 0 && r.Details != "" && r.Details != "-" {
+			reasons = append(reasons, r.Details)
+		}
+		return fmt.Sprintf("Take a closer look before depending on %s: %s.", r.Name, strings.Join(reasons, "; "))
+
+	default:
+		return fmt.Sprintf("%s looks legitimate: it exists, has some history, and nothing stood out.", r.Name)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", t.Format("2006-01-02"), utils.HumanDuration(time.Since(t)))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
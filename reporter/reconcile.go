@@ -0,0 +1,39 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Kelcode-Dev/vibe-validator/scanner"
+)
+
+// PrintReconciliation lists imports no manifest declares and declared
+// dependencies nothing imports
+func PrintReconciliation(rec scanner.Reconciliation) {
+	fmt.Print("\n[vibe-validator] Declared vs Imported Report\n\n")
+
+	if len(rec.Undeclared) == 0 && len(rec.Unused) == 0 {
+		fmt.Println("✅ Every import is declared and every declared dependency is imported.")
+		return
+	}
+
+	printGaps("undeclared (imported, but no manifest declares them; possibly hallucinated):", "Imported at", rec.Undeclared)
+	printGaps("unused (declared, but never imported):", "Declared in", rec.Unused)
+}
+
+func printGaps(title, pathHeader string, gaps []scanner.Gap) {
+	if len(gaps) == 0 {
+		return
+	}
+
+	fmt.Println(title)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  Ecosystem\tName\t%s\n", pathHeader)
+	for _, g := range gaps {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", g.Ecosystem, g.Name, strings.Join(g.Paths, ", "))
+	}
+	w.Flush()
+	fmt.Println()
+}
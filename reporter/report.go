@@ -10,13 +10,19 @@ import (
 	"github.com/Kelcode-Dev/vibe-validator/validator"
 )
 
-func PrintReport(results []validator.ValidationResult, verbosity int) {
+// Options controls what the report includes
+type Options struct {
+	Verbosity int
+	MinScore  float64 // hide dependencies scoring below this
+}
+
+func PrintReport(results []validator.ValidationResult, opts Options) {
 	if len(results) == 0 {
 		fmt.Println("No dependencies found.")
 		return
 	}
 
-	fmt.Println("\n[vibe-validator] Dependency Vibe Report\n")
+	fmt.Print("\n[vibe-validator] Dependency Vibe Report\n\n")
 
 	groups := map[string][]validator.ValidationResult{}
 	for _, r := range results {
@@ -27,9 +33,12 @@ func PrintReport(results []validator.ValidationResult, verbosity int) {
 		filtered := []validator.ValidationResult{}
 		for _, r := range group {
 			// Filter by verbosity:
-			if verbosity == 0 && r.Status == "safe" {
+			if opts.Verbosity == 0 && r.Status == "safe" {
 				continue // default: hide safe
 			}
+			if r.Score < opts.MinScore {
+				continue
+			}
 			filtered = append(filtered, r)
 		}
 
@@ -37,29 +46,112 @@ func PrintReport(results []validator.ValidationResult, verbosity int) {
 			continue
 		}
 
+		// riskiest first
 		sort.Slice(filtered, func(i, j int) bool {
+			if filtered[i].Score != filtered[j].Score {
+				return filtered[i].Score > filtered[j].Score
+			}
 			return filtered[i].Name < filtered[j].Name
 		})
 
 		fmt.Printf("%s:\n", eco)
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "  Status\tName\tDetails\tPath")
+		fmt.Fprintln(w, "  Status\tScore\tName\tDetails\tPath")
 
 		for _, r := range filtered {
 			icon := map[string]string{
 				"safe":        "[✓]",
 				"investigate": "[~]",
 				"not_found":   "[✗]",
+				"malicious":   "[!]",
 			}[r.Status]
 
 			detail := r.Details
+			for _, f := range r.Findings {
+				if isLicenseFinding(f) {
+					continue // reported in their own section
+				}
+				if detail == "" || detail == "-" {
+					detail = f.Details
+				} else {
+					detail += "; " + f.Details
+				}
+			}
 			if detail == "" {
 				detail = "-"
 			}
+			if r.Change != "" {
+				if detail == "-" {
+					detail = "[" + r.Change + "]"
+				} else {
+					detail = "[" + r.Change + "] " + detail
+				}
+			}
 			paths := strings.Join(r.Paths, ", ")
-			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", icon, r.Name, detail, paths)
+			fmt.Fprintf(w, "  %s\t%.0f\t%s\t%s\t%s\n", icon, r.Score, r.Name, detail, paths)
+
+			if opts.Verbosity >= 2 {
+				for _, s := range r.Signals {
+					if s.Points > 0 {
+						fmt.Fprintf(w, "  \t\t  +%.1f %s\t%s\t\n", s.Points, s.Name, s.Value)
+					}
+				}
+			}
 		}
 		w.Flush()
 		fmt.Println()
 	}
+
+	printLicenses(results)
+}
+
+// printLicenses lists license findings separately from the vibe check, as a
+// disallowed license says nothing about whether a package is legitimate
+func printLicenses(results []validator.ValidationResult) {
+	var flagged []validator.ValidationResult
+	for _, r := range results {
+		for _, f := range r.Findings {
+			if isLicenseFinding(f) {
+				flagged = append(flagged, r)
+				break
+			}
+		}
+	}
+	if len(flagged) == 0 {
+		return
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		if flagged[i].Source != flagged[j].Source {
+			return flagged[i].Source < flagged[j].Source
+		}
+		return flagged[i].Name < flagged[j].Name
+	})
+
+	fmt.Println("licenses:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Status\tEcosystem\tName\tLicense\tDetails")
+
+	for _, r := range flagged {
+		license := r.License
+		if license == "" {
+			license = "-"
+		}
+		for _, f := range r.Findings {
+			if !isLicenseFinding(f) {
+				continue
+			}
+			icon := "[~]"
+			if f.Type == "license_denied" || f.Type == "license_not_allowed" {
+				icon = "[✗]"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", icon, r.Source, r.Name, license, f.Details)
+		}
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+func isLicenseFinding(f validator.Finding) bool {
+	return strings.HasPrefix(f.Type, "license_")
 }
@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// usesPattern matches a step's or job's uses: line
+var usesPattern = regexp.MustCompile(`^\s*(?:-\s+)?uses:\s*["']?([^"'\s#]+)`)
+
+// ScanActions finds the actions and reusable workflows used by a project's
+// GitHub workflows and composite actions, keyed by owner/repo with
+// "file:line" locations. The refs they are used at are the versions.
+// Local actions (./path) and docker:// images are left out.
+func ScanActions(projectPath string, includeVendor bool, verbosity int) (DepMap, DepMap, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning GitHub Actions...")
+	}
+	deps := make(DepMap)
+	versions := make(DepMap)
+
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != projectPath && (info.Name() == ".git" || (!includeVendor && vendorDirs[info.Name()])) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if IsWorkflow(path) {
+			if data, err := os.ReadFile(path); err == nil {
+				parseWorkflow(path, data, deps, versions)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if verbosity >= 2 {
+		fmt.Printf("Finished scanning GitHub Actions... %d deps found\n\n", len(deps))
+	}
+	return deps, versions, nil
+}
+
+// IsWorkflow reports whether a file is a GitHub workflow
+// (.github/workflows/*.yml) or an action definition (action.yml)
+func IsWorkflow(path string) bool {
+	base := filepath.Base(path)
+	if base == "action.yml" || base == "action.yaml" {
+		return true
+	}
+	ext := filepath.Ext(base)
+	return (ext == ".yml" || ext == ".yaml") && strings.HasSuffix(filepath.ToSlash(filepath.Dir(path)), ".github/workflows")
+}
+
+// parseWorkflow records each owner/repo[/path]@ref a workflow uses. Lines
+// are read one at a time rather than as YAML, so locations are exact and a
+// broken workflow still gets checked.
+func parseWorkflow(path string, data []byte, deps, versions DepMap) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		m := usesPattern.FindStringSubmatch(scanner.Text())
+		if m == nil || strings.HasPrefix(m[1], ".") || strings.HasPrefix(m[1], "docker://") {
+			continue
+		}
+
+		action, ref, _ := strings.Cut(m[1], "@")
+		parts := strings.Split(action, "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		name := parts[0] + "/" + parts[1]
+		deps[name] = append(deps[name], fmt.Sprintf("%s:%d", path, lineNo))
+		addVersion(versions, name, ref)
+	}
+}
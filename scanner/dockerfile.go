@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// heredocPattern matches the start of a RUN heredoc (RUN <<EOF, <<-"EOF")
+var heredocPattern = regexp.MustCompile(`<<-?\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+
+// ScanDockerfiles finds packages installed by name in Dockerfile RUN
+// instructions (pip install, npm install -g, go install, ...), keyed by
+// ecosystem with "Dockerfile:line" locations. Go package paths are
+// attributed to their module with resolve when given.
+func ScanDockerfiles(projectPath string, includeVendor bool, verbosity int, resolve func(string) (string, bool)) (AllDeps, AllVersions, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning Dockerfiles...")
+	}
+	deps := make(AllDeps)
+	versions := make(AllVersions)
+
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != projectPath && (info.Name() == ".git" || (!includeVendor && vendorDirs[info.Name()])) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if IsDockerfile(path) {
+			if data, err := os.ReadFile(path); err == nil {
+				addInstalls(deps, versions, lineLocation(path), parseDockerfile(data), resolve)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if verbosity >= 2 {
+		count := 0
+		for _, names := range deps {
+			count += len(names)
+		}
+		fmt.Printf("Finished scanning Dockerfiles... %d deps found\n\n", count)
+	}
+	return deps, versions, nil
+}
+
+// IsDockerfile reports whether a file name is a Dockerfile or Containerfile,
+// including variants like Dockerfile.dev and app.Dockerfile
+func IsDockerfile(path string) bool {
+	base := filepath.Base(path)
+	for _, name := range []string{"Dockerfile", "Containerfile"} {
+		if base == name || strings.HasPrefix(base, name+".") || strings.HasSuffix(base, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDockerfile finds the packages installed by RUN instructions, in
+// shell form (with continuations), exec form or heredocs
+func parseDockerfile(data []byte) []scriptInstall {
+	var installs []scriptInstall
+	lines := strings.Split(string(data), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		first := i + 1
+		word, rest, _ := strings.Cut(strings.TrimSpace(lines[i]), " ")
+		if strings.HasPrefix(word, "#") {
+			continue
+		}
+		if strings.EqualFold(word, "ONBUILD") {
+			word, rest, _ = strings.Cut(strings.TrimSpace(rest), " ")
+		}
+
+		// gather the instruction's continuation lines. Docker drops comment
+		// lines inside an instruction; they become bare continuations so
+		// the line numbers still add up.
+		text := rest
+		for line := lines[i]; strings.HasSuffix(strings.TrimSpace(line), "\\") && i+1 < len(lines); {
+			i++
+			line = lines[i]
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				line = "\\"
+			}
+			text += "\n" + line
+		}
+		if !strings.EqualFold(word, "RUN") {
+			continue
+		}
+		text = stripRunFlags(text)
+
+		if strings.HasPrefix(text, "[") {
+			var args []string
+			if json.Unmarshal([]byte(text), &args) != nil || len(args) == 0 {
+				continue
+			}
+			if script, ok := shellScriptArg(args); ok {
+				installs = append(installs, shellInstalls(script, first)...)
+			} else {
+				installs = append(installs, commandInstalls(commandWords(args), first)...)
+			}
+			continue
+		}
+
+		if m := heredocPattern.FindStringSubmatchIndex(text); m != nil {
+			delimiter := text[m[2]:m[3]]
+			var body []string
+			start := i + 2
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != delimiter; i++ {
+				body = append(body, lines[i])
+			}
+			// only a heredoc fed to the shell is a script
+			if runner := strings.TrimSpace(text[:m[0]]); runner == "" || isShell(runner) {
+				installs = append(installs, shellInstalls(strings.Join(body, "\n"), start)...)
+			}
+			text = text[:m[0]]
+		}
+		installs = append(installs, shellInstalls(text, first)...)
+	}
+	return installs
+}
+
+// stripRunFlags drops RUN's own options (--mount=..., --network=...)
+func stripRunFlags(text string) string {
+	text = strings.TrimSpace(text)
+	for strings.HasPrefix(text, "--") {
+		_, text, _ = strings.Cut(text, " ")
+		text = strings.TrimSpace(text)
+	}
+	return text
+}
@@ -3,7 +3,6 @@ package scanner
 import (
 	"fmt"
 	"golang.org/x/mod/modfile"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 )
@@ -11,11 +10,12 @@ import (
 // GoDeps maps module names to list of file paths where found
 type GoDeps map[string][]string
 
-func ScanGo(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (GoDeps, error) {
+func ScanGo(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (GoDeps, DepMap, error) {
 	if verbosity >= 2 {
 		fmt.Println("Scanning Go...")
 	}
 	deps := make(GoDeps)
+	versions := make(DepMap)
 
 	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -31,41 +31,32 @@ func ScanGo(projectPath string, includeLockfiles, includeVendor bool, verbosity
 		}
 
 		if filepath.Base(path) == "go.mod" {
-			modDeps, err := parseGoMod(path)
-			if err == nil {
-				for mod := range modDeps {
-					deps[mod] = append(deps[mod], path)
-				}
-			}
+			parseFile(path, deps, versions)
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if verbosity >= 2 {
 		fmt.Printf("Finished scanning Go... %d deps found\n\n", len(deps))
 	}
-	return deps, nil
+	return deps, versions, nil
 }
 
-func parseGoMod(path string) (map[string]struct{}, error) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
+// parseGoMod extracts required modules and their versions from go.mod
+func parseGoMod(path string, data []byte, deps, versions DepMap) error {
 	f, err := modfile.Parse(path, data, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	mods := make(map[string]struct{})
 	for _, req := range f.Require {
-		mods[req.Mod.Path] = struct{}{}
+		deps[req.Mod.Path] = append(deps[req.Mod.Path], path)
+		addVersion(versions, req.Mod.Path, req.Mod.Version)
 	}
-	return mods, nil
+	return nil
 }
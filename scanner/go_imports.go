@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ScanGoImports finds the modules a project's .go files import that no
+// go.mod in the project requires, keyed by module with "file:line"
+// locations. Standard library packages and the project's own modules are
+// left out. Imports are attributed to a module with resolve (which probes
+// the Go proxy) when given, and otherwise reported by import path.
+func ScanGoImports(projectPath string, includeVendor bool, verbosity int, resolve func(string) (string, bool)) (DepMap, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning Go imports...")
+	}
+
+	imports, requires, err := goSourceImports(projectPath, includeVendor)
+	if err != nil {
+		return nil, err
+	}
+	var required []string
+	for _, req := range requires {
+		required = append(required, req.Mod.Path)
+	}
+
+	deps := make(DepMap)
+	var resolved []string // modules found by probing, so siblings aren't probed again
+	for path, locations := range imports {
+		if goModuleFor(path, required) != "" {
+			continue
+		}
+
+		module := goModuleFor(path, resolved)
+		if module == "" {
+			module = path
+			if resolve != nil {
+				if m, found := resolve(path); found {
+					module = m
+					resolved = append(resolved, m)
+				}
+			}
+		}
+		deps[module] = append(deps[module], locations...)
+	}
+
+	if verbosity >= 2 {
+		fmt.Printf("Finished scanning Go imports... %d modules found\n\n", len(deps))
+	}
+	return deps, nil
+}
+
+// goSourceImports reads the non-standard import paths of a project's .go
+// files, keyed by import path with "file:line" locations, leaving out the
+// project's own modules. It also returns what the project's go.mod files
+// require.
+func goSourceImports(projectPath string, includeVendor bool) (DepMap, []*modfile.Require, error) {
+	var files []string
+	var own []string
+	var requires []*modfile.Require
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			// the go tool ignores testdata and _-prefixed directories too
+			if path != projectPath && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" ||
+				(!includeVendor && vendorDirs[name])) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case strings.HasSuffix(path, ".go"):
+			files = append(files, path)
+		case info.Name() == "go.mod":
+			if data, err := os.ReadFile(path); err == nil {
+				if f, err := modfile.ParseLax(path, data, nil); err == nil {
+					if f.Module != nil {
+						own = append(own, f.Module.Mod.Path)
+					}
+					requires = append(requires, f.Require...)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imports := make(DepMap)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || isGoStdlib(path) || goModuleFor(path, own) != "" {
+				continue
+			}
+			imports[path] = append(imports[path], fmt.Sprintf("%s:%d", file, fset.Position(spec.Pos()).Line))
+		}
+	}
+	return imports, requires, nil
+}
+
+// isGoStdlib reports whether an import path belongs to the standard
+// library, whose first element never contains a dot
+func isGoStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// goModuleFor returns the longest module path that provides an import
+// path, or "" if none of them do
+func goModuleFor(path string, modules []string) string {
+	best := ""
+	for _, m := range modules {
+		if (path == m || strings.HasPrefix(path, m+"/")) && len(m) > len(best) {
+			best = m
+		}
+	}
+	return best
+}
@@ -0,0 +1,307 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// InstallPackage is a package named in a package-manager install command
+type InstallPackage struct {
+	Ecosystem string
+	Name      string
+	Version   string // only set when the command pins an exact version
+}
+
+// valueFlags are options that consume the next argument, per tool, so the
+// value isn't mistaken for a package name
+var valueFlags = map[string][]string{
+	"npm":      {"--registry", "--prefix", "-w", "--workspace", "--tag", "--cache", "--userconfig", "--omit", "--include", "--before", "--install-strategy"},
+	"yarn":     {"--registry", "--cwd", "--modules-folder", "--cache-folder"},
+	"pnpm":     {"--registry", "--filter", "-F", "--dir", "-C", "--store-dir"},
+	"pip":      {"-r", "--requirement", "-c", "--constraint", "-e", "--editable", "-i", "--index-url", "--extra-index-url", "-t", "--target", "--prefix", "--root", "-f", "--find-links", "--platform", "--python-version", "--implementation", "--abi", "--trusted-host", "--src", "--upgrade-strategy", "--progress-bar", "--cache-dir", "--log", "--proxy", "--timeout", "--retries", "--python", "--no-binary", "--only-binary", "--use-feature", "-C", "--config-settings", "--global-option", "--report"},
+	"uv":       {"--group", "--optional", "--index", "--index-url", "--default-index", "--extra-index-url", "--python", "-p", "--package", "--directory", "--project", "-r", "--requirements", "-c", "--constraint", "--tag", "--branch", "--rev", "--extra", "--no-binary", "--only-binary", "--no-binary-package", "--no-build-package", "-C", "--config-setting"},
+	"poetry":   {"--group", "-G", "--source", "--extras", "-E", "--python", "--platform", "--directory", "-C"},
+	"go":       {"-C", "-modfile", "-tags", "-ldflags", "-gcflags", "-o", "-p"},
+	"cargo":    {"-F", "--features", "--path", "--git", "--branch", "--tag", "--rev", "--registry", "--rename", "-p", "--package", "--manifest-path", "--version", "--vers", "--root", "--index", "--target", "--target-dir", "-j", "--jobs", "--profile", "--bin", "--example"},
+	"composer": {"--working-dir", "-d"},
+	"conda":    {"-c", "--channel", "-n", "--name", "-p", "--prefix", "--file", "--repodata-fn"},
+	"gem":      {"-v", "--version", "-i", "--install-dir", "-n", "--bindir", "-s", "--source", "--platform", "-P", "--trust-policy", "-g", "--file"},
+	"bundle":   {"-v", "--version", "-g", "--group", "-s", "--source", "--git", "--branch", "--ref", "--path", "--require"},
+}
+
+// Registry name rules, so values of flags we don't know about and other
+// stray words aren't looked up as packages
+var (
+	npmNamePattern   = regexp.MustCompile(`^(@[a-zA-Z0-9][\w.~-]*/)?[a-zA-Z0-9][\w.~-]*$`)
+	pypiNamePattern  = regexp.MustCompile(`^[a-zA-Z0-9]([\w.-]*[a-zA-Z0-9])?$`)
+	crateNamePattern = regexp.MustCompile(`^[a-zA-Z][\w-]*$`)
+	gemNamePattern   = regexp.MustCompile(`^[a-zA-Z0-9][\w.-]*$`)
+)
+
+// ParseInstallCommand extracts the packages a package-manager command line
+// would install, e.g. ["npm", "install", "-D", "left-pad@1.3.0"]. Commands
+// that don't install anything by name (plain "npm install") yield nothing.
+func ParseInstallCommand(args []string) []InstallPackage {
+	if len(args) == 0 {
+		return nil
+	}
+
+	tool := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	rest := args[1:]
+
+	// python -m pip install ..., pip3, pip3.12
+	if strings.HasPrefix(tool, "python") && len(rest) >= 2 && rest[0] == "-m" {
+		tool, rest = rest[1], rest[2:]
+	}
+	if strings.HasPrefix(tool, "pip") {
+		tool = "pip"
+	}
+
+	switch tool {
+	case "npm":
+		if names := afterVerb(rest, tool, "install", "i", "add", "isntall", "in", "ins", "inst", "insta", "instal"); names != nil {
+			return npmPackages(names)
+		}
+	case "yarn":
+		if names := afterVerb(skipWord(rest, "global"), tool, "add"); names != nil {
+			return npmPackages(names)
+		}
+	case "pnpm":
+		if names := afterVerb(rest, tool, "add", "install", "i"); names != nil {
+			return npmPackages(names)
+		}
+	case "pip":
+		if names := afterVerb(rest, tool, "install"); names != nil {
+			return pypiPackages(names)
+		}
+	case "uv":
+		if len(rest) > 0 && rest[0] == "pip" {
+			return pypiPackages(afterVerb(rest[1:], tool, "install"))
+		}
+		return pypiPackages(afterVerb(rest, tool, "add"))
+	case "poetry":
+		return pypiPackages(afterVerb(rest, tool, "add"))
+	case "go":
+		return goPackages(afterVerb(rest, tool, "get", "install"))
+	case "cargo":
+		return cratePackages(afterVerb(rest, tool, "add", "install"), rest)
+	case "conda", "mamba", "micromamba":
+		// conda names mostly match PyPI's; channel::name picks the channel
+		var names []string
+		for _, a := range afterVerb(rest, "conda", "install") {
+			if _, name, found := strings.Cut(a, "::"); found {
+				a = name
+			}
+			names = append(names, a)
+		}
+		return pypiPackages(names)
+	case "composer":
+		return composerPackages(afterVerb(skipWord(rest, "global"), tool, "require", "req"))
+	case "gem":
+		return gemPackages(afterVerb(rest, tool, "install", "i"), rest)
+	case "bundle":
+		return gemPackages(afterVerb(rest, tool, "add"), rest)
+	}
+	return nil
+}
+
+// afterVerb returns the non-flag arguments following the subcommand when it
+// is one of verbs, or nil if the command is something else
+func afterVerb(args []string, tool string, verbs ...string) []string {
+	takesValue := map[string]bool{}
+	for _, f := range valueFlags[tool] {
+		takesValue[f] = true
+	}
+
+	var positional []string
+	skip := false
+	for _, a := range args {
+		switch {
+		case skip:
+			skip = false
+		case a == "--":
+			// everything after -- is for the tool being installed
+			goto done
+		case strings.HasPrefix(a, "-"):
+			if !strings.Contains(a, "=") && takesValue[a] {
+				skip = true
+			}
+		default:
+			positional = append(positional, a)
+		}
+	}
+done:
+
+	if len(positional) == 0 {
+		return nil
+	}
+	for _, v := range verbs {
+		if positional[0] == v {
+			return append([]string{}, positional[1:]...)
+		}
+	}
+	return nil
+}
+
+func skipWord(args []string, word string) []string {
+	for i, a := range args {
+		if a == word {
+			return append(append([]string{}, args[:i]...), args[i+1:]...)
+		}
+	}
+	return args
+}
+
+// isLocalRef spots paths, URLs and VCS references, which name no registry package
+func isLocalRef(s string) bool {
+	return strings.Contains(s, "://") || strings.HasPrefix(s, ".") || strings.HasPrefix(s, "/") ||
+		strings.HasPrefix(s, "~") || strings.HasPrefix(s, "git+") || strings.HasPrefix(s, "file:") ||
+		strings.HasSuffix(s, ".tgz") || strings.HasSuffix(s, ".tar.gz") || strings.HasSuffix(s, ".whl") ||
+		strings.HasSuffix(s, ".zip") || strings.HasSuffix(s, ".gem")
+}
+
+// exactVersion keeps a version only when it pins one release
+func exactVersion(v string) string {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "=")
+	if v == "" || strings.Trim(v, "0123456789.") != "" {
+		return ""
+	}
+	return v
+}
+
+func npmPackages(args []string) []InstallPackage {
+	var pkgs []InstallPackage
+	for _, a := range args {
+		if isLocalRef(a) {
+			continue
+		}
+		// alias@npm:real-name@version installs real-name
+		if _, target, found := strings.Cut(a, "@npm:"); found {
+			a = target
+		}
+		name, version := a, ""
+		if at := strings.LastIndex(a, "@"); at > 0 {
+			name, version = a[:at], a[at+1:]
+		}
+		// owner/repo is a GitHub shorthand, not a registry name
+		if !strings.HasPrefix(name, "@") && strings.Contains(name, "/") {
+			continue
+		}
+		if !npmNamePattern.MatchString(name) {
+			continue
+		}
+		pkgs = append(pkgs, InstallPackage{Ecosystem: "npm", Name: name, Version: exactVersion(version)})
+	}
+	return pkgs
+}
+
+func pypiPackages(args []string) []InstallPackage {
+	var pkgs []InstallPackage
+	for _, a := range args {
+		if isLocalRef(a) {
+			continue
+		}
+		// poetry accepts name@^1.0 as well as PEP 508 specifiers
+		a = strings.Replace(a, "@", "", 1)
+		name, version := a, ""
+		if idx := strings.Index(a, "=="); idx != -1 {
+			version = exactVersion(a[idx+2:])
+		}
+		if idx := strings.IndexAny(name, "[<>=!~;^ "); idx != -1 {
+			name = name[:idx]
+		}
+		if name = strings.TrimSpace(name); pypiNamePattern.MatchString(name) {
+			pkgs = append(pkgs, InstallPackage{Ecosystem: "pypi", Name: name, Version: version})
+		}
+	}
+	return pkgs
+}
+
+func goPackages(args []string) []InstallPackage {
+	var pkgs []InstallPackage
+	for _, a := range args {
+		path, version, _ := strings.Cut(a, "@")
+		// local packages and the standard library have no dot in the first element
+		first, _, _ := strings.Cut(path, "/")
+		if isLocalRef(path) || !strings.Contains(first, ".") {
+			continue
+		}
+		// @latest, @master and friends name no particular release
+		if !semver.IsValid(version) {
+			version = ""
+		}
+		pkgs = append(pkgs, InstallPackage{Ecosystem: "go", Name: strings.TrimSuffix(path, "/..."), Version: version})
+	}
+	return pkgs
+}
+
+func cratePackages(args, all []string) []InstallPackage {
+	version := flagValue(all, "--version", "--vers")
+
+	var pkgs []InstallPackage
+	for _, a := range args {
+		name, v, found := strings.Cut(a, "@")
+		if !found {
+			v = version
+		}
+		if !crateNamePattern.MatchString(name) {
+			continue
+		}
+		pkgs = append(pkgs, InstallPackage{Ecosystem: "rust", Name: name, Version: exactVersion(v)})
+	}
+	return pkgs
+}
+
+func composerPackages(args []string) []InstallPackage {
+	var pkgs []InstallPackage
+	for i := 0; i < len(args); i++ {
+		name, version, _ := strings.Cut(args[i], ":")
+		if !strings.Contains(name, "/") {
+			continue // a bare version following "vendor/package"
+		}
+		if version == "" && i+1 < len(args) && !strings.Contains(args[i+1], "/") {
+			version = args[i+1]
+		}
+		pkgs = append(pkgs, InstallPackage{Ecosystem: "php", Name: strings.ToLower(name), Version: exactVersion(strings.TrimPrefix(version, "v"))})
+	}
+	return pkgs
+}
+
+func gemPackages(args, all []string) []InstallPackage {
+	version := flagValue(all, "-v", "--version")
+
+	var pkgs []InstallPackage
+	for _, a := range args {
+		if isLocalRef(a) {
+			continue
+		}
+		// gem install name:1.2.3
+		name, v, found := strings.Cut(a, ":")
+		if !found {
+			v = version
+		}
+		if !gemNamePattern.MatchString(name) {
+			continue
+		}
+		pkgs = append(pkgs, InstallPackage{Ecosystem: "ruby", Name: name, Version: exactVersion(v)})
+	}
+	return pkgs
+}
+
+// flagValue returns the value given to any of the named flags
+func flagValue(args []string, names ...string) string {
+	for i, a := range args {
+		for _, n := range names {
+			if a == n && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(a, n+"=") {
+				return strings.TrimPrefix(a, n+"=")
+			}
+		}
+	}
+	return ""
+}
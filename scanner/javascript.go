@@ -3,20 +3,21 @@ package scanner
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // JsDeps maps dependency name to list of file paths where found
 type JsDeps map[string][]string
 
 // ScanJavaScript scans the given project directory for JS ecosystem deps
-func ScanJavaScript(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (JsDeps, error) {
+func ScanJavaScript(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (JsDeps, DepMap, error) {
 	if verbosity >= 2 {
 		fmt.Println("Scanning JavaScript...")
 	}
 	deps := make(JsDeps)
+	versions := make(DepMap)
 
 	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -34,33 +35,29 @@ func ScanJavaScript(projectPath string, includeLockfiles, includeVendor bool, ve
 		base := filepath.Base(path)
 		switch base {
 		case "package.json":
-			parsePackageJSON(path, deps)
+			parseFile(path, deps, versions)
 
 		case "package-lock.json", "yarn.lock", "pnpm-lock.yaml":
 			if includeLockfiles {
-				parseLockfile(path, deps)
+				parseFile(path, deps, versions)
 			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if verbosity >= 2 {
 		fmt.Printf("Finished scanning JavaScript... %d deps found\n\n", len(deps))
 	}
-	return deps, nil
+	return deps, versions, nil
 }
 
-// parsePackageJSON extracts dependencies & devDependencies from package.json
-func parsePackageJSON(path string, deps JsDeps) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
+// parsePackageJSON extracts dependencies & devDependencies from package.json;
+// only exact versions count as pinned, ranges are left to the lockfile
+func parsePackageJSON(path string, data []byte, deps, versions DepMap) error {
 	var obj struct {
 		Dependencies    map[string]interface{} `json:"dependencies"`
 		DevDependencies map[string]interface{} `json:"devDependencies"`
@@ -70,26 +67,34 @@ func parsePackageJSON(path string, deps JsDeps) error {
 		return err
 	}
 
-	for name := range obj.Dependencies {
+	for name, spec := range obj.Dependencies {
 		deps[name] = append(deps[name], path)
+		addExactVersion(versions, name, spec)
 	}
-	for name := range obj.DevDependencies {
+	for name, spec := range obj.DevDependencies {
 		deps[name] = append(deps[name], path)
+		addExactVersion(versions, name, spec)
 	}
 
 	return nil
 }
 
-// parseLockfile parses npm/yarn/pnpm lockfiles to gather all locked deps
-func parseLockfile(path string, deps JsDeps) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
+// addExactVersion records a package.json spec when it is a plain version
+func addExactVersion(versions DepMap, name string, spec interface{}) {
+	if v, ok := spec.(string); ok && v != "" && strings.Trim(v, "0123456789.") == "" {
+		addVersion(versions, name, v)
 	}
+}
 
-	// npm lockfile structure
+// parseLockfile parses npm/yarn/pnpm lockfiles to gather all locked deps
+func parseLockfile(path string, data []byte, deps, versions DepMap) error {
+	// npm lockfile structure: v1 nests "dependencies", v2+ lists every
+	// installed path under "packages"
 	var lock struct {
 		Dependencies map[string]interface{} `json:"dependencies"`
+		Packages     map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
 	}
 
 	if err := json.Unmarshal(data, &lock); err != nil {
@@ -102,6 +107,9 @@ func parseLockfile(path string, deps JsDeps) error {
 		for name, val := range depsMap {
 			deps[name] = append(deps[name], path)
 			if depInfo, ok := val.(map[string]interface{}); ok {
+				if v, ok := depInfo["version"].(string); ok {
+					addVersion(versions, name, v)
+				}
 				if nestedDeps, ok := depInfo["dependencies"].(map[string]interface{}); ok {
 					collect(nestedDeps)
 				}
@@ -110,5 +118,26 @@ func parseLockfile(path string, deps JsDeps) error {
 	}
 	collect(lock.Dependencies)
 
+	for pkgPath, pkg := range lock.Packages {
+		idx := strings.LastIndex(pkgPath, "node_modules/")
+		if idx == -1 {
+			continue // the root project itself
+		}
+		name := pkgPath[idx+len("node_modules/"):]
+		if !containsPath(deps[name], path) {
+			deps[name] = append(deps[name], path)
+		}
+		addVersion(versions, name, pkg.Version)
+	}
+
 	return nil
 }
+
+func containsPath(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
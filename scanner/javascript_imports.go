@@ -0,0 +1,263 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// nodeBuiltins are Node's built-in modules, importable with or without the
+// node: prefix
+var nodeBuiltins = toSet(
+	"assert", "async_hooks", "buffer", "child_process", "cluster", "console",
+	"constants", "crypto", "dgram", "diagnostics_channel", "dns", "domain",
+	"events", "fs", "http", "http2", "https", "inspector", "module", "net", "os",
+	"path", "perf_hooks", "process", "punycode", "querystring", "readline",
+	"repl", "stream", "string_decoder", "sys", "test", "timers", "tls",
+	"trace_events", "tty", "url", "util", "v8", "vm", "wasi", "worker_threads",
+	"zlib",
+)
+
+var jsSourceExts = toSet(".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx")
+
+// jsImportPatterns match the module specifier of require(), dynamic
+// import(), bare import '...', and import/export ... from '...'
+var jsImportPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\brequire\s*\(\s*['"]([^'"\n]+)['"]\s*\)`),
+	regexp.MustCompile(`\bimport\s*\(\s*['"]([^'"\n]+)['"]\s*[,)]`),
+	regexp.MustCompile(`\bimport\s+['"]([^'"\n]+)['"]`),
+	regexp.MustCompile(`\b(?:import|export)\s[^'";]*?\bfrom\s*['"]([^'"\n]+)['"]`),
+}
+
+// ScanJavaScriptImports finds the npm packages a project's JS/TS files
+// import, keyed by package name with "file:line" locations. Relative
+// paths, Node built-ins and the project's own workspace packages are left
+// out.
+func ScanJavaScriptImports(projectPath string, includeVendor bool, verbosity int) (DepMap, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning JavaScript imports...")
+	}
+
+	var files, aliases []string
+	local := map[string]bool{}
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != projectPath && (strings.HasPrefix(info.Name(), ".") || (!includeVendor && vendorDirs[info.Name()])) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case jsSourceExts[filepath.Ext(path)]:
+			files = append(files, path)
+		case info.Name() == "package.json":
+			// workspace packages import each other by name
+			if data, err := os.ReadFile(path); err == nil {
+				var pkg struct {
+					Name string `json:"name"`
+				}
+				if json.Unmarshal(data, &pkg) == nil && pkg.Name != "" {
+					local[pkg.Name] = true
+				}
+			}
+		case isJSConfig(info.Name()):
+			// path aliases and baseUrl imports resolve to project files
+			patterns, names := jsConfigAliases(path)
+			aliases = append(aliases, patterns...)
+			for _, name := range names {
+				local[name] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make(DepMap)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, imp := range parseJSImports(data) {
+			name := npmPackageName(imp.module)
+			if name == "" || local[name] || matchesAlias(imp.module, aliases) {
+				continue
+			}
+			deps[name] = append(deps[name], fmt.Sprintf("%s:%d", file, imp.line))
+		}
+	}
+
+	if verbosity >= 2 {
+		fmt.Printf("Finished scanning JavaScript imports... %d packages found\n\n", len(deps))
+	}
+	return deps, nil
+}
+
+// isJSConfig spots tsconfig.json, jsconfig.json and variants such as
+// tsconfig.base.json
+func isJSConfig(name string) bool {
+	return strings.HasSuffix(name, ".json") &&
+		(strings.HasPrefix(name, "tsconfig.") || strings.HasPrefix(name, "jsconfig."))
+}
+
+// trailingComma matches the trailing commas tsconfig files tolerate
+var trailingComma = regexp.MustCompile(`,(\s*[}\]])`)
+
+// jsConfigAliases reads a tsconfig/jsconfig's compilerOptions: the paths
+// patterns ("@app/*", "components/*") and the top-level files and
+// directories under baseUrl, which bare specifiers also resolve to
+func jsConfigAliases(path string) ([]string, []string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+	code, _ := stripJSComments(data)
+
+	var config struct {
+		CompilerOptions struct {
+			BaseURL string              `json:"baseUrl"`
+			Paths   map[string][]string `json:"paths"`
+		} `json:"compilerOptions"`
+	}
+	if err := json.Unmarshal(trailingComma.ReplaceAll(code, []byte("$1")), &config); err != nil {
+		return nil, nil
+	}
+
+	var patterns []string
+	for pattern := range config.CompilerOptions.Paths {
+		// a bare "*" usually falls back to node_modules
+		if pattern != "*" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	var names []string
+	if config.CompilerOptions.BaseURL != "" {
+		entries, _ := os.ReadDir(filepath.Join(filepath.Dir(path), config.CompilerOptions.BaseURL))
+		for _, e := range entries {
+			name := e.Name()
+			if strings.HasPrefix(name, ".") || name == "node_modules" {
+				continue
+			}
+			if !e.IsDir() {
+				if !jsSourceExts[filepath.Ext(name)] {
+					continue
+				}
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			names = append(names, name)
+		}
+	}
+	return patterns, names
+}
+
+// matchesAlias reports whether a specifier matches a paths pattern, where
+// a trailing * matches anything
+func matchesAlias(spec string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, wildcard := strings.CutSuffix(p, "*"); wildcard {
+			if strings.HasPrefix(spec, prefix) {
+				return true
+			}
+		} else if spec == p {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSImports finds module specifiers outside of comments and strings
+func parseJSImports(data []byte) []sourceImport {
+	code, quoted := stripJSComments(data)
+
+	var imports []sourceImport
+	seen := map[int]bool{}
+	for _, pattern := range jsImportPatterns {
+		for _, m := range pattern.FindAllSubmatchIndex(code, -1) {
+			// the patterns overlap (import x from 'y' also looks like a
+			// bare import to nothing), so count each specifier once
+			if seen[m[2]] || quoted[m[0]] {
+				continue
+			}
+			seen[m[2]] = true
+			imports = append(imports, sourceImport{
+				module: string(code[m[2]:m[3]]),
+				line:   bytes.Count(code[:m[0]], []byte("\n")) + 1,
+			})
+		}
+	}
+	return imports
+}
+
+// stripJSComments blanks out // and /* */ comments, leaving line breaks
+// where they were so offsets still map to lines. It also marks which bytes
+// are inside string literals, where import-like text isn't an import.
+func stripJSComments(data []byte) ([]byte, []bool) {
+	out := make([]byte, len(data))
+	copy(out, data)
+	quoted := make([]bool, len(data))
+
+	var quote byte
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case quote != 0:
+			quoted[i] = true
+			if c == '\\' {
+				i++
+			} else if c == quote || (c == '\n' && quote != '`') {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			for ; i < len(out) && !(out[i] == '*' && i+1 < len(out) && out[i+1] == '/'); i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			if i+1 < len(out) {
+				out[i], out[i+1] = ' ', ' '
+				i++
+			}
+		}
+	}
+	return out, quoted
+}
+
+// npmPackageName reduces a specifier to its package ("lodash/fp" is
+// lodash, "@scope/pkg/sub" is @scope/pkg), or "" when it names no package.
+// package.json subpath imports always start with #, so they're left out
+// here.
+func npmPackageName(spec string) string {
+	if spec == "" || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") ||
+		strings.HasPrefix(spec, "node:") || strings.HasPrefix(spec, "#") ||
+		strings.HasPrefix(spec, "~") || strings.HasPrefix(spec, "@/") || strings.Contains(spec, ":") {
+		return ""
+	}
+
+	parts := strings.Split(spec, "/")
+	if strings.HasPrefix(spec, "@") {
+		if len(parts) < 2 {
+			return ""
+		}
+		return parts[0] + "/" + parts[1]
+	}
+	if nodeBuiltins[parts[0]] {
+		return ""
+	}
+	return parts[0]
+}
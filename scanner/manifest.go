@@ -0,0 +1,220 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// manifest describes how to read one kind of dependency file
+type manifest struct {
+	Ecosystem string
+	Lockfile  bool
+	parse     func(path string, data []byte, deps, versions DepMap) error
+}
+
+// manifests maps file names to their parsers
+var manifests = map[string]manifest{
+	"requirements.txt":  {"pypi", false, parseRequirements},
+	"Pipfile.lock":      {"pypi", true, parsePipfileLock},
+	"package.json":      {"npm", false, parsePackageJSON},
+	"package-lock.json": {"npm", true, parseLockfile},
+	"yarn.lock":         {"npm", true, parseLockfile},
+	"pnpm-lock.yaml":    {"npm", true, parseLockfile},
+	"go.mod":            {"go", false, parseGoMod},
+	"composer.json":     {"php", false, parseComposerJSON},
+	"composer.lock":     {"php", true, parseComposerLock},
+	"Gemfile":           {"ruby", false, parseGemfile},
+	"Gemfile.lock":      {"ruby", true, parseGemfileLock},
+	"Cargo.toml":        {"rust", false, parseCargoToml},
+	"Cargo.lock":        {"rust", true, parseCargoLock},
+}
+
+// vendorDirs are the directories the scanners skip unless told to include
+// vendored code
+var vendorDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "Godeps": true, ".venv": true,
+	"venv": true, "env": true, "site-packages": true, "__pycache__": true,
+	"target": true,
+}
+
+// parseFile reads a manifest from disk into deps and versions
+func parseFile(path string, deps, versions DepMap) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return manifests[filepath.Base(path)].parse(path, data, deps, versions)
+}
+
+// ParseManifest parses a manifest or lockfile from memory, e.g. an editor
+// buffer or a file in git, picking the parser by file name. The ecosystem
+// is empty for files no scanner understands.
+func ParseManifest(path string, data []byte) (string, DepMap, DepMap, error) {
+	m, found := manifests[filepath.Base(path)]
+	if !found {
+		return "", nil, nil, nil
+	}
+
+	deps, versions := make(DepMap), make(DepMap)
+	err := m.parse(path, data, deps, versions)
+	return m.Ecosystem, deps, versions, err
+}
+
+// MergeManifest parses a manifest from memory and adds its dependencies to
+// a scan's results, as if the scanners had found it at path
+func MergeManifest(deps AllDeps, versions AllVersions, path string, data []byte) error {
+	eco, found, pinned, err := ParseManifest(path, data)
+	if err != nil || eco == "" {
+		return err
+	}
+
+	if deps[eco] == nil {
+		deps[eco] = make(DepMap)
+	}
+	if versions[eco] == nil {
+		versions[eco] = make(DepMap)
+	}
+	for name, paths := range found {
+		deps[eco][name] = append(deps[eco][name], paths...)
+		for _, v := range pinned[name] {
+			addVersion(versions[eco], name, v)
+		}
+	}
+	return nil
+}
+
+// IsLockfile reports whether a file name is a lockfile, which scans only
+// read when asked to
+func IsLockfile(path string) bool {
+	return manifests[filepath.Base(path)].Lockfile
+}
+
+// InVendorDir reports whether a slash-separated path runs through a
+// directory of vendored or installed packages
+func InVendorDir(path string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if vendorDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVendorDir reports whether a directory name is one of vendored or
+// installed packages
+func IsVendorDir(name string) bool {
+	return vendorDirs[name]
+}
+
+// IsManifest reports whether a file name is one the scanners read
+func IsManifest(path string) bool {
+	_, found := manifests[filepath.Base(path)]
+	return found
+}
+
+// IsInstallSource reports whether a file is one the scanners read for the
+// packages it installs or uses rather than declares: Dockerfiles, GitHub
+// workflows, scripts, Makefiles, Markdown and Jupyter notebooks
+func IsInstallSource(path string) bool {
+	return IsDockerfile(path) || IsWorkflow(path) || scriptExtractor(path) != nil || filepath.Ext(path) == ".ipynb"
+}
+
+// MergeInstallSource parses an install source from memory, e.g. a file in
+// git, and adds what it installs or uses to a scan's results, as if the
+// scanners had found it at path. Go package paths are attributed to their
+// module with resolve when given.
+func MergeInstallSource(deps AllDeps, versions AllVersions, path string, data []byte, resolve func(string) (string, bool)) {
+	switch {
+	case IsDockerfile(path):
+		addInstalls(deps, versions, lineLocation(path), parseDockerfile(data), resolve)
+	case IsWorkflow(path):
+		if deps["actions"] == nil {
+			deps["actions"] = make(DepMap)
+			versions["actions"] = make(DepMap)
+		}
+		parseWorkflow(path, data, deps["actions"], versions["actions"])
+	case scriptExtractor(path) != nil:
+		parseScript(path, data, deps, versions, resolve)
+	case filepath.Ext(path) == ".ipynb":
+		visitNotebook(path, data, 0, notebookInstalls(deps, versions, resolve))
+	}
+}
+
+// Declaration is where a manifest names a dependency. Lines and columns
+// are zero-based; columns count bytes.
+type Declaration struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	Line      int
+	Column    int
+	EndColumn int
+}
+
+// Declarations parses a manifest and locates each dependency in its text,
+// for editors that need to point at an entry rather than the file
+func Declarations(path string, data []byte) ([]Declaration, error) {
+	eco, deps, versions, err := ParseManifest(path, data)
+	if err != nil || eco == "" {
+		return nil, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	var decls []Declaration
+	for name := range deps {
+		line, col := locateName(lines, name)
+		if line < 0 {
+			continue
+		}
+		d := Declaration{Ecosystem: eco, Name: name, Line: line, Column: col, EndColumn: col + len(name)}
+		if len(versions[name]) == 1 {
+			d.Version = versions[name][0]
+		}
+		decls = append(decls, d)
+	}
+
+	sort.Slice(decls, func(i, j int) bool {
+		return decls[i].Line < decls[j].Line
+	})
+	return decls, nil
+}
+
+// locateName finds the first place a dependency name appears as a whole
+// word, preferring a JSON or TOML key ("name": / name =) so a project's own
+// "name" field doesn't shadow a dependency of the same name
+func locateName(lines []string, name string) (int, int) {
+	firstLine, firstCol := -1, -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		for from := 0; ; {
+			idx := strings.Index(line[from:], name)
+			if idx == -1 {
+				break
+			}
+			start, end := from+idx, from+idx+len(name)
+			from = end
+			if (start > 0 && isNameChar(line[start-1])) || (end < len(line) && isNameChar(line[end])) {
+				continue
+			}
+
+			after := strings.TrimLeft(strings.TrimPrefix(line[end:], `"`), " \t")
+			if strings.HasPrefix(after, ":") || strings.HasPrefix(after, "=") && !strings.HasPrefix(after, "==") {
+				return i, start
+			}
+			if firstLine == -1 {
+				firstLine, firstCol = i, start
+			}
+		}
+	}
+	return firstLine, firstCol
+}
+
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '/' || c == '@'
+}
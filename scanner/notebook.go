@@ -0,0 +1,203 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// notebook is the part of the Jupyter .ipynb format the scanner reads
+type notebook struct {
+	Cells []struct {
+		CellType string          `json:"cell_type"`
+		Source   json.RawMessage `json:"source"` // a string, or a list of lines
+	} `json:"cells"`
+	Metadata struct {
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+// pythonCellMagics are cell magics whose body is still Python
+var pythonCellMagics = toSet("%%capture", "%%time", "%%timeit", "%%prun")
+
+// ScanNotebooks finds the packages Jupyter notebooks install (!pip
+// install, %pip install, %conda install), keyed by ecosystem with
+// "notebook.ipynb[cell N]:line" locations, counting cells and lines from 1.
+// Downloads piped into a shell are recorded as ScanScripts does.
+func ScanNotebooks(projectPath string, includeVendor bool, verbosity int, resolve func(string) (string, bool)) (AllDeps, AllVersions, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning notebooks...")
+	}
+	deps := make(AllDeps)
+	versions := make(AllVersions)
+
+	err := walkNotebookCells(projectPath, includeVendor, verbosity, notebookInstalls(deps, versions, resolve))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if verbosity >= 2 {
+		count := 0
+		for _, names := range deps {
+			count += len(names)
+		}
+		fmt.Printf("Finished scanning notebooks... %d deps found\n\n", count)
+	}
+	return deps, versions, nil
+}
+
+// notebookInstalls returns a cell visitor recording what cells install
+func notebookInstalls(deps AllDeps, versions AllVersions, resolve func(string) (string, bool)) func(func(int) string, string) {
+	return func(locate func(int) string, source string) {
+		_, shell := splitCell(source)
+		addInstalls(deps, versions, locate, shellInstalls(shell, 1), resolve)
+		addRemoteScripts(deps, locate, remoteScripts(shell, 1))
+	}
+}
+
+// ScanNotebookImports finds the PyPI distributions Jupyter notebooks
+// import, resolved as for .py files, with "notebook.ipynb[cell N]:line"
+// locations
+func ScanNotebookImports(projectPath string, includeVendor bool, verbosity int) (DepMap, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning notebook imports...")
+	}
+
+	// notebooks import the project's modules too
+	_, local, err := pythonSources(projectPath, includeVendor)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make(DepMap)
+	err = walkNotebookCells(projectPath, includeVendor, verbosity, func(locate func(int) string, source string) {
+		code, _ := splitCell(source)
+		for _, imp := range parsePythonImports([]byte(code)) {
+			if dist := importedDistribution(imp.module, local); dist != "" {
+				deps[dist] = append(deps[dist], locate(imp.line))
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if verbosity >= 2 {
+		fmt.Printf("Finished scanning notebook imports... %d packages found\n\n", len(deps))
+	}
+	return deps, nil
+}
+
+// walkNotebookCells calls visit with the source of each code cell in a
+// project's Python notebooks, and a function locating the cell's lines
+func walkNotebookCells(projectPath string, includeVendor bool, verbosity int, visit func(locate func(int) string, source string)) error {
+	var files []string
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			// .ipynb_checkpoints holds autosaved copies
+			if path != projectPath && (strings.HasPrefix(info.Name(), ".") || (!includeVendor && vendorDirs[info.Name()])) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".ipynb" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		visitNotebook(file, data, verbosity, visit)
+	}
+	return nil
+}
+
+// visitNotebook calls visit for each code cell of one notebook, unless its
+// kernel isn't Python
+func visitNotebook(file string, data []byte, verbosity int, visit func(locate func(int) string, source string)) {
+	var nb notebook
+	if err := json.Unmarshal(data, &nb); err != nil {
+		if verbosity >= 2 {
+			fmt.Printf("Skipping %s: %v\n", file, err)
+		}
+		return
+	}
+	if lang := nb.Metadata.Kernelspec.Language + nb.Metadata.LanguageInfo.Name; lang != "" && !strings.Contains(strings.ToLower(lang), "python") {
+		return
+	}
+
+	for i, cell := range nb.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		visit(func(line int) string {
+			return fmt.Sprintf("%s[cell %d]:%d", file, i+1, line)
+		}, cellSource(cell.Source))
+	}
+}
+
+// cellSource joins a cell's source, which nbformat stores either way
+func cellSource(raw json.RawMessage) string {
+	var lines []string
+	if json.Unmarshal(raw, &lines) == nil {
+		return strings.Join(lines, "")
+	}
+	var source string
+	json.Unmarshal(raw, &source)
+	return source
+}
+
+// splitCell separates a cell's Python from its shell commands: ! lines,
+// %pip and %conda magics (and their automagic spellings), and %%bash
+// cells. Both keep every line, blank where the other one has it, so line
+// numbers match the cell.
+func splitCell(source string) (string, string) {
+	lines := strings.Split(source, "\n")
+	code := make([]string, len(lines))
+	shell := make([]string, len(lines))
+
+	if magic := strings.Fields(lines[0]); len(magic) > 0 && strings.HasPrefix(magic[0], "%%") {
+		switch {
+		case magic[0] == "%%bash" || magic[0] == "%%sh" || (magic[0] == "%%script" && len(magic) > 1 && isShell(magic[1])):
+			copy(shell[1:], lines[1:])
+			return "", strings.Join(shell, "\n")
+		case !pythonCellMagics[magic[0]]:
+			// %%html, %%writefile and the like aren't Python
+			return "", ""
+		}
+		lines[0] = ""
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		word, _, _ := strings.Cut(strings.TrimPrefix(trimmed, "%"), " ")
+		switch {
+		case strings.HasPrefix(trimmed, "!"):
+			shell[i] = strings.TrimLeft(trimmed, "!")
+		case word == "pip" || word == "conda" || word == "mamba":
+			shell[i] = strings.TrimPrefix(trimmed, "%")
+		case strings.HasPrefix(trimmed, "%"):
+			// other line magics
+		default:
+			code[i] = line
+		}
+	}
+	return strings.Join(code, "\n"), strings.Join(shell, "\n")
+}
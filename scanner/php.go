@@ -3,7 +3,6 @@ package scanner
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 )
@@ -12,11 +11,12 @@ import (
 type PhpDeps map[string][]string
 
 // ScanPHP scans for PHP Composer dependencies in a project directory
-func ScanPHP(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (PhpDeps, error) {
+func ScanPHP(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (PhpDeps, DepMap, error) {
 	if verbosity >= 2 {
 		fmt.Println("Scanning PHP...")
 	}
 	deps := make(PhpDeps)
+	versions := make(DepMap)
 
 	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -34,10 +34,10 @@ func ScanPHP(projectPath string, includeLockfiles, includeVendor bool, verbosity
 		base := filepath.Base(path)
 		switch base {
 		case "composer.json":
-			parseComposerJSON(path, deps)
+			parseFile(path, deps, versions)
 		case "composer.lock":
 			if includeLockfiles {
-				parseComposerLock(path, deps)
+				parseFile(path, deps, versions)
 			}
 		}
 
@@ -45,22 +45,17 @@ func ScanPHP(projectPath string, includeLockfiles, includeVendor bool, verbosity
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if verbosity >= 2 {
 		fmt.Printf("Finished scanning PHP... %d deps found\n\n", len(deps))
 	}
-	return deps, nil
+	return deps, versions, nil
 }
 
 // parseComposerJSON extracts dependencies from composer.json (require & require-dev)
-func parseComposerJSON(path string, deps PhpDeps) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
+func parseComposerJSON(path string, data []byte, deps, versions DepMap) error {
 	var obj struct {
 		Require    map[string]interface{} `json:"require"`
 		RequireDev map[string]interface{} `json:"require-dev"`
@@ -80,19 +75,16 @@ func parseComposerJSON(path string, deps PhpDeps) error {
 	return nil
 }
 
-// parseComposerLock extracts dependencies from composer.lock
-func parseComposerLock(path string, deps PhpDeps) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
+// parseComposerLock extracts dependencies and locked versions from composer.lock
+func parseComposerLock(path string, data []byte, deps, versions DepMap) error {
 	var lock struct {
 		Packages []struct {
-			Name string `json:"name"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
 		} `json:"packages"`
 		DevPackages []struct {
-			Name string `json:"name"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
 		} `json:"packages-dev"`
 	}
 
@@ -102,9 +94,11 @@ func parseComposerLock(path string, deps PhpDeps) error {
 
 	for _, pkg := range lock.Packages {
 		deps[pkg.Name] = append(deps[pkg.Name], path)
+		addVersion(versions, pkg.Name, pkg.Version)
 	}
 	for _, pkg := range lock.DevPackages {
 		deps[pkg.Name] = append(deps[pkg.Name], path)
+		addVersion(versions, pkg.Name, pkg.Version)
 	}
 
 	return nil
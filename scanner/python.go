@@ -3,7 +3,6 @@ package scanner
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,11 +12,12 @@ import (
 type PyDeps map[string][]string
 
 // ScanPython scans the given project directory for Python ecosystem deps
-func ScanPython(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (PyDeps, error) {
+func ScanPython(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (PyDeps, DepMap, error) {
 	if verbosity >= 2 {
 		fmt.Println("Scanning Python...")
 	}
 	deps := make(PyDeps)
+	versions := make(DepMap)
 
 	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -35,32 +35,28 @@ func ScanPython(projectPath string, includeLockfiles, includeVendor bool, verbos
 		base := filepath.Base(path)
 		switch base {
 		case "requirements.txt":
-			parseRequirements(path, deps)
+			parseFile(path, deps, versions)
 		case "Pipfile.lock":
 			if includeLockfiles {
-				parsePipfileLock(path, deps)
+				parseFile(path, deps, versions)
 			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if verbosity >= 2 {
 		fmt.Printf("Finished scanning Python... %d deps found\n\n", len(deps))
 	}
-	return deps, nil
+	return deps, versions, nil
 }
 
-// parseRequirements extracts package names from requirements.txt
-func parseRequirements(path string, deps PyDeps) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
+// parseRequirements extracts package names from requirements.txt, along with
+// any exact (==) pins
+func parseRequirements(path string, data []byte, deps, versions DepMap) error {
 	lines := strings.Split(string(data), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -69,6 +65,13 @@ func parseRequirements(path string, deps PyDeps) error {
 		}
 		// Strip extras and version operators
 		name := line
+		var pin string
+		if idx := strings.Index(name, "=="); idx != -1 {
+			pin = strings.TrimLeft(name[idx+2:], "=")
+			if end := strings.IndexAny(pin, "; ,#"); end != -1 {
+				pin = pin[:end]
+			}
+		}
 		if idx := strings.Index(name, "["); idx != -1 {
 			name = name[:idx]
 		}
@@ -80,30 +83,30 @@ func parseRequirements(path string, deps PyDeps) error {
 		name = strings.TrimSpace(name)
 		if name != "" {
 			deps[name] = append(deps[name], path)
+			addVersion(versions, name, strings.TrimSpace(pin))
 		}
 	}
 	return nil
 }
 
-// parsePipfileLock extracts package names from Pipfile.lock
-func parsePipfileLock(path string, deps PyDeps) error {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
+// parsePipfileLock extracts package names and locked versions from Pipfile.lock
+func parsePipfileLock(path string, data []byte, deps, versions DepMap) error {
+	type lockedPackage struct {
+		Version string `json:"version"`
 	}
-
 	var lock struct {
-		Default map[string]interface{} `json:"default"`
-		Develop map[string]interface{} `json:"develop"`
+		Default map[string]lockedPackage `json:"default"`
+		Develop map[string]lockedPackage `json:"develop"`
 	}
 
 	if err := json.Unmarshal(data, &lock); err != nil {
 		return err
 	}
 
-	collectDeps := func(depMap map[string]interface{}) {
-		for name := range depMap {
+	collectDeps := func(depMap map[string]lockedPackage) {
+		for name, pkg := range depMap {
 			deps[name] = append(deps[name], path)
+			addVersion(versions, name, strings.TrimPrefix(pkg.Version, "=="))
 		}
 	}
 
@@ -0,0 +1,292 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pythonStdlib lists the standard library's top-level modules (Python 3.11's
+// sys.stdlib_module_names, private modules aside)
+var pythonStdlib = toSet(
+	"abc", "aifc", "antigravity", "argparse", "array", "ast", "asynchat",
+	"asyncio", "asyncore", "atexit", "audioop", "base64", "bdb", "binascii",
+	"bisect", "builtins", "bz2", "cProfile", "calendar", "cgi", "cgitb",
+	"chunk", "cmath", "cmd", "code", "codecs", "codeop", "collections",
+	"colorsys", "compileall", "concurrent", "configparser", "contextlib",
+	"contextvars", "copy", "copyreg", "crypt", "csv", "ctypes", "curses",
+	"dataclasses", "datetime", "dbm", "decimal", "difflib", "dis", "distutils",
+	"doctest", "email", "encodings", "ensurepip", "enum", "errno",
+	"faulthandler", "fcntl", "filecmp", "fileinput", "fnmatch", "fractions",
+	"ftplib", "functools", "gc", "genericpath", "getopt", "getpass", "gettext",
+	"glob", "graphlib", "grp", "gzip", "hashlib", "heapq", "hmac", "html",
+	"http", "idlelib", "imaplib", "imghdr", "imp", "importlib", "inspect", "io",
+	"ipaddress", "itertools", "json", "keyword", "lib2to3", "linecache",
+	"locale", "logging", "lzma", "mailbox", "mailcap", "marshal", "math",
+	"mimetypes", "mmap", "modulefinder", "msilib", "msvcrt", "multiprocessing",
+	"netrc", "nis", "nntplib", "nt", "ntpath", "nturl2path", "numbers",
+	"opcode", "operator", "optparse", "os", "ossaudiodev", "pathlib", "pdb",
+	"pickle", "pickletools", "pipes", "pkgutil", "platform", "plistlib",
+	"poplib", "posix", "posixpath", "pprint", "profile", "pstats", "pty", "pwd",
+	"py_compile", "pyclbr", "pydoc", "pydoc_data", "pyexpat", "queue", "quopri",
+	"random", "re", "readline", "reprlib", "resource", "rlcompleter", "runpy",
+	"sched", "secrets", "select", "selectors", "shelve", "shlex", "shutil",
+	"signal", "site", "smtpd", "smtplib", "sndhdr", "socket", "socketserver",
+	"spwd", "sqlite3", "sre_compile", "sre_constants", "sre_parse", "ssl",
+	"stat", "statistics", "string", "stringprep", "struct", "subprocess",
+	"sunau", "symtable", "sys", "sysconfig", "syslog", "tabnanny", "tarfile",
+	"telnetlib", "tempfile", "termios", "textwrap", "this", "threading", "time",
+	"timeit", "tkinter", "token", "tokenize", "tomllib", "trace", "traceback",
+	"tracemalloc", "tty", "turtle", "turtledemo", "types", "typing",
+	"unicodedata", "unittest", "urllib", "uu", "uuid", "venv", "warnings",
+	"wave", "weakref", "webbrowser", "winreg", "winsound", "wsgiref", "xdrlib",
+	"xml", "xmlrpc", "zipapp", "zipfile", "zipimport", "zlib", "zoneinfo",
+)
+
+// pythonDistributions maps import names to the PyPI distribution providing
+// them where the two differ. Dotted keys match the longest prefix.
+var pythonDistributions = map[string]string{
+	"yaml":                  "PyYAML",
+	"cv2":                   "opencv-python",
+	"sklearn":               "scikit-learn",
+	"skimage":               "scikit-image",
+	"PIL":                   "Pillow",
+	"bs4":                   "beautifulsoup4",
+	"dateutil":              "python-dateutil",
+	"dotenv":                "python-dotenv",
+	"jwt":                   "PyJWT",
+	"jose":                  "python-jose",
+	"magic":                 "python-magic",
+	"multipart":             "python-multipart",
+	"docx":                  "python-docx",
+	"pptx":                  "python-pptx",
+	"telegram":              "python-telegram-bot",
+	"slugify":               "python-slugify",
+	"serial":                "pyserial",
+	"usb":                   "pyusb",
+	"Crypto":                "pycryptodome",
+	"OpenSSL":               "pyOpenSSL",
+	"nacl":                  "PyNaCl",
+	"git":                   "GitPython",
+	"github":                "PyGithub",
+	"gitlab":                "python-gitlab",
+	"MySQLdb":               "mysqlclient",
+	"ldap":                  "python-ldap",
+	"zmq":                   "pyzmq",
+	"attr":                  "attrs",
+	"pkg_resources":         "setuptools",
+	"win32api":              "pywin32",
+	"win32com":              "pywin32",
+	"fitz":                  "PyMuPDF",
+	"Levenshtein":           "python-Levenshtein",
+	"websocket":             "websocket-client",
+	"socks":                 "PySocks",
+	"google.protobuf":       "protobuf",
+	"google.auth":           "google-auth",
+	"google.oauth2":         "google-auth",
+	"googleapiclient":       "google-api-python-client",
+	"grpc":                  "grpcio",
+	"faiss":                 "faiss-cpu",
+	"discord":               "discord.py",
+	"kafka":                 "kafka-python",
+	"dns":                   "dnspython",
+	"Bio":                   "biopython",
+	"mpl_toolkits":          "matplotlib",
+	"wx":                    "wxPython",
+	"gi":                    "PyGObject",
+	"cairo":                 "pycairo",
+	"rest_framework":        "djangorestframework",
+	"corsheaders":           "django-cors-headers",
+	"environ":               "django-environ",
+	"debug_toolbar":         "django-debug-toolbar",
+	"sentence_transformers": "sentence-transformers",
+}
+
+// ScanPythonImports finds the distributions a project's .py files import,
+// keyed by distribution name with "file:line" locations. Standard library
+// modules, relative imports and modules that live in the project itself
+// are left out.
+func ScanPythonImports(projectPath string, includeVendor bool, verbosity int) (DepMap, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning Python imports...")
+	}
+
+	files, local, err := pythonSources(projectPath, includeVendor)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := make(DepMap)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, imp := range parsePythonImports(data) {
+			if dist := importedDistribution(imp.module, local); dist != "" {
+				deps[dist] = append(deps[dist], fmt.Sprintf("%s:%d", file, imp.line))
+			}
+		}
+	}
+
+	if verbosity >= 2 {
+		fmt.Printf("Finished scanning Python imports... %d distributions found\n\n", len(deps))
+	}
+	return deps, nil
+}
+
+// pythonSources lists a project's .py files and the top-level module names
+// they make importable, skipping hidden and (unless asked) vendored
+// directories such as .venv so installed packages aren't taken for the
+// project's own. Only modules and packages sitting directly in an import
+// root count: the project root, src/ layouts and sub-projects with their own
+// pyproject.toml, setup.py or setup.cfg.
+func pythonSources(projectPath string, includeVendor bool) ([]string, map[string]bool, error) {
+	var files []string
+	roots := map[string]bool{filepath.Clean(projectPath): true}
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != projectPath && (strings.HasPrefix(info.Name(), ".") || (!includeVendor && vendorDirs[info.Name()])) {
+				return filepath.SkipDir
+			}
+			if info.Name() == "src" {
+				roots[filepath.Clean(path)] = true
+			}
+			return nil
+		}
+		switch info.Name() {
+		case "pyproject.toml", "setup.py", "setup.cfg":
+			roots[filepath.Dir(path)] = true
+		}
+		if strings.HasSuffix(path, ".py") {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	local := map[string]bool{}
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if roots[dir] {
+			// a top-level module
+			local[strings.TrimSuffix(filepath.Base(file), ".py")] = true
+		}
+		if roots[filepath.Dir(dir)] {
+			// a package, or a namespace package of plain modules
+			local[filepath.Base(dir)] = true
+		}
+	}
+	return files, local, err
+}
+
+// sourceImport is a module named by an import statement
+type sourceImport struct {
+	module string
+	line   int
+}
+
+// parsePythonImports reads absolute module names from import and from
+// statements, skipping anything inside triple-quoted strings
+func parsePythonImports(data []byte) []sourceImport {
+	var imports []sourceImport
+	inString := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		if inString != "" {
+			if strings.Count(line, inString)%2 == 1 {
+				inString = ""
+			}
+			continue
+		}
+		for _, quote := range []string{`"""`, `'''`} {
+			if strings.Count(line, quote)%2 == 1 {
+				inString = quote
+			}
+		}
+		if inString != "" {
+			continue
+		}
+
+		line, _, _ = strings.Cut(line, "#")
+		switch {
+		case strings.HasPrefix(line, "import "):
+			// import a.b, c as d
+			for _, part := range strings.Split(strings.TrimPrefix(line, "import "), ",") {
+				if fields := strings.Fields(part); len(fields) > 0 && fields[0] != "\\" {
+					imports = append(imports, sourceImport{module: fields[0], line: lineNo})
+				}
+			}
+		case strings.HasPrefix(line, "from "):
+			// from a.b import c; relative imports start with a dot
+			fields := strings.Fields(line)
+			if len(fields) < 3 || fields[2] != "import" || strings.HasPrefix(fields[1], ".") {
+				continue
+			}
+			// the imported names may be submodules, which is what tells
+			// namespace packages apart (from google.cloud import storage)
+			added := false
+			for _, name := range strings.Split(strings.Join(fields[3:], " "), ",") {
+				name = strings.Trim(strings.TrimSpace(name), "()\\ ")
+				name, _, _ = strings.Cut(name, " ")
+				if name != "" && name != "*" {
+					imports = append(imports, sourceImport{module: fields[1] + "." + name, line: lineNo})
+					added = true
+				}
+			}
+			if !added {
+				imports = append(imports, sourceImport{module: fields[1], line: lineNo})
+			}
+		}
+	}
+	return imports
+}
+
+// importedDistribution maps an imported module to its distribution, or ""
+// for standard library, private and project modules
+func importedDistribution(module string, local map[string]bool) string {
+	top, _, _ := strings.Cut(module, ".")
+	if top == "" || strings.HasPrefix(top, "_") || pythonStdlib[top] || local[top] {
+		return ""
+	}
+	return pythonDistribution(module)
+}
+
+// pythonDistribution maps a dotted module path to its distribution name,
+// or "" for namespace packages that can't be attributed
+func pythonDistribution(module string) string {
+	parts := strings.Split(module, ".")
+	for n := min(len(parts), 3); n > 0; n-- {
+		if dist, found := pythonDistributions[strings.Join(parts[:n], ".")]; found {
+			return dist
+		}
+	}
+
+	// google.cloud.storage is google-cloud-storage, and so on
+	if len(parts) >= 3 && parts[0] == "google" && parts[1] == "cloud" {
+		return "google-cloud-" + strings.ReplaceAll(parts[2], "_", "-")
+	}
+	if parts[0] == "google" || parts[0] == "azure" {
+		return ""
+	}
+	return parts[0]
+}
+
+func toSet(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+)
+
+// Gap is a dependency that is imported but not declared, or declared but
+// not imported, with the places it appears
+type Gap struct {
+	Ecosystem string
+	Name      string
+	Paths     []string
+}
+
+// Reconciliation compares what a project's manifests declare with what
+// its source files import
+type Reconciliation struct {
+	Undeclared []Gap // imported, but no manifest declares it
+	Unused     []Gap // declared, but never imported
+}
+
+// Reconcile matches the Python, JavaScript/TypeScript and Go imports in a
+// project against its manifests (lockfiles aside, as they list transitive
+// dependencies too). Go imports are matched to required modules by path
+// prefix and reported by import path when none provides them; indirect
+// requirements are never reported unused, nor are npm @types packages.
+func Reconcile(path string, includeVendor bool) (Reconciliation, error) {
+	var rec Reconciliation
+
+	// tools a Dockerfile or script installs aren't meant to be imported
+	declared, _ := ScanManifests(path, ScanOptions{IncludeVendor: includeVendor})
+
+	pyImports, err := ScanPythonImports(path, includeVendor, 0)
+	if err != nil {
+		return rec, err
+	}
+	nbImports, err := ScanNotebookImports(path, includeVendor, 0)
+	if err != nil {
+		return rec, err
+	}
+	pyImports = mergeDeps(pyImports, nbImports)
+	jsImports, err := ScanJavaScriptImports(path, includeVendor, 0)
+	if err != nil {
+		return rec, err
+	}
+	rec.compare("pypi", declared["pypi"], pyImports)
+	rec.compare("npm", declared["npm"], jsImports)
+
+	goImports, requires, err := goSourceImports(path, includeVendor)
+	if err != nil {
+		return rec, err
+	}
+	var required []string
+	used := map[string]bool{}
+	direct := map[string]bool{}
+	for _, req := range requires {
+		required = append(required, req.Mod.Path)
+		if !req.Indirect {
+			direct[req.Mod.Path] = true
+		}
+	}
+	for importPath, locations := range goImports {
+		if module := goModuleFor(importPath, required); module != "" {
+			used[module] = true
+		} else {
+			rec.Undeclared = append(rec.Undeclared, Gap{Ecosystem: "go", Name: importPath, Paths: locations})
+		}
+	}
+	for module, paths := range declared["go"] {
+		if !used[module] && direct[module] {
+			rec.Unused = append(rec.Unused, Gap{Ecosystem: "go", Name: module, Paths: paths})
+		}
+	}
+
+	for _, gaps := range [][]Gap{rec.Undeclared, rec.Unused} {
+		sort.Slice(gaps, func(i, j int) bool {
+			if gaps[i].Ecosystem != gaps[j].Ecosystem {
+				return gaps[i].Ecosystem < gaps[j].Ecosystem
+			}
+			return gaps[i].Name < gaps[j].Name
+		})
+	}
+	return rec, nil
+}
+
+// compare records the gaps between one ecosystem's declared and imported
+// names, matched the way the registry matches them
+func (rec *Reconciliation) compare(eco string, declared, imported DepMap) {
+	declaredNames := map[string]bool{}
+	for name := range declared {
+		declaredNames[osv.NormalizeName(eco, name)] = true
+	}
+	importedNames := map[string]bool{}
+	for name, locations := range imported {
+		importedNames[osv.NormalizeName(eco, name)] = true
+		if !declaredNames[osv.NormalizeName(eco, name)] {
+			rec.Undeclared = append(rec.Undeclared, Gap{Ecosystem: eco, Name: name, Paths: locations})
+		}
+	}
+	for name, paths := range declared {
+		// type packages are used by the compiler, never imported
+		if eco == "npm" && strings.HasPrefix(name, "@types/") {
+			continue
+		}
+		if !importedNames[osv.NormalizeName(eco, name)] {
+			rec.Unused = append(rec.Unused, Gap{Ecosystem: eco, Name: name, Paths: paths})
+		}
+	}
+}
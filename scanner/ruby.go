@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,11 +12,12 @@ import (
 // RubyDeps maps gem name to list of file paths where found
 type RubyDeps map[string][]string
 
-func ScanRuby(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (RubyDeps, error) {
+func ScanRuby(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (RubyDeps, DepMap, error) {
 	if verbosity >= 2 {
 		fmt.Println("Scanning Ruby...")
 	}
 	deps := make(RubyDeps)
+	versions := make(DepMap)
 
 	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -33,34 +35,28 @@ func ScanRuby(projectPath string, includeLockfiles, includeVendor bool, verbosit
 		base := filepath.Base(path)
 		switch base {
 		case "Gemfile":
-			parseGemfile(path, deps)
+			parseFile(path, deps, versions)
 		case "Gemfile.lock":
 			if includeLockfiles {
-				parseGemfileLock(path, deps)
+				parseFile(path, deps, versions)
 			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if verbosity >= 2 {
 		fmt.Printf("Finished scanning Ruby... %d deps found\n\n", len(deps))
 	}
-	return deps, nil
+	return deps, versions, nil
 }
 
 // parseGemfile extracts gem names from Gemfile by looking for lines like: gem 'name', 'version'
-func parseGemfile(path string, deps RubyDeps) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
+func parseGemfile(path string, data []byte, deps, versions DepMap) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if strings.HasPrefix(line, "gem ") {
@@ -76,19 +72,15 @@ func parseGemfile(path string, deps RubyDeps) error {
 	return scanner.Err()
 }
 
-// parseGemfileLock extracts gems from Gemfile.lock by parsing lines under "GEM" section
-func parseGemfileLock(path string, deps RubyDeps) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
+// parseGemfileLock extracts gems and their locked versions from Gemfile.lock by
+// parsing lines under "GEM" section
+func parseGemfileLock(path string, data []byte, deps, versions DepMap) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	inGemSection := false
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
 		if line == "GEM" {
 			inGemSection = true
 			continue
@@ -97,10 +89,13 @@ func parseGemfileLock(path string, deps RubyDeps) error {
 			if line == "" {
 				break
 			}
-			// line format:   gem_name (version)
-			if strings.HasPrefix(line, "  ") && strings.Contains(line, " ") {
-				gemName := strings.Fields(line)[0]
+			// line format:     gem_name (version), with the gem's own
+			// requirements indented a level deeper
+			if strings.HasPrefix(raw, "    ") && !strings.HasPrefix(raw, "     ") && strings.Contains(line, " (") {
+				fields := strings.Fields(line)
+				gemName := fields[0]
 				deps[gemName] = append(deps[gemName], path)
+				addVersion(versions, gemName, strings.Trim(fields[1], "()"))
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,11 +14,12 @@ import (
 // RustDeps maps crate name to list of file paths where found
 type RustDeps map[string][]string
 
-func ScanRust(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (RustDeps, error) {
+func ScanRust(projectPath string, includeLockfiles, includeVendor bool, verbosity int) (RustDeps, DepMap, error) {
 	if verbosity >= 2 {
 		fmt.Println("Scanning Ruby...")
 	}
 	deps := make(RustDeps)
+	versions := make(DepMap)
 
 	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -35,33 +37,28 @@ func ScanRust(projectPath string, includeLockfiles, includeVendor bool, verbosit
 		base := filepath.Base(path)
 		switch base {
 		case "Cargo.toml":
-			parseCargoToml(path, deps)
+			parseFile(path, deps, versions)
 		case "Cargo.lock":
 			if includeLockfiles {
-				parseCargoLock(path, deps)
+				parseFile(path, deps, versions)
 			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if verbosity >= 2 {
 		fmt.Printf("Finished scanning Rust... %d deps found\n\n", len(deps))
 	}
-	return deps, nil
+	return deps, versions, nil
 }
 
 // parseCargoToml extracts dependencies from Cargo.toml [dependencies] and [dev-dependencies]
-func parseCargoToml(path string, deps RustDeps) error {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return err
-	}
-
-	tree, err := toml.LoadBytes(content)
+func parseCargoToml(path string, data []byte, deps, versions DepMap) error {
+	tree, err := toml.LoadBytes(data)
 	if err != nil {
 		return err
 	}
@@ -87,16 +84,10 @@ func parseCargoToml(path string, deps RustDeps) error {
 	return nil
 }
 
-// parseCargoLock extracts dependencies from Cargo.lock file
-func parseCargoLock(path string, deps RustDeps) error {
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var currentPkg string
+// parseCargoLock extracts dependencies and locked versions from Cargo.lock file
+func parseCargoLock(path string, data []byte, deps, versions DepMap) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var currentPkg, currentVersion string
 	inPackage := false
 
 	for scanner.Scan() {
@@ -104,15 +95,20 @@ func parseCargoLock(path string, deps RustDeps) error {
 		if line == "[[package]]" {
 			inPackage = true
 			currentPkg = ""
+			currentVersion = ""
 			continue
 		}
 		if inPackage {
 			if strings.HasPrefix(line, "name = ") {
 				currentPkg = strings.Trim(line[len("name = "):], "\"")
 			}
+			if strings.HasPrefix(line, "version = ") {
+				currentVersion = strings.Trim(line[len("version = "):], "\"")
+			}
 			if line == "" {
 				if currentPkg != "" {
 					deps[currentPkg] = append(deps[currentPkg], path)
+					addVersion(versions, currentPkg, currentVersion)
 				}
 				inPackage = false
 			}
@@ -121,6 +117,7 @@ func parseCargoLock(path string, deps RustDeps) error {
 	// Handle last package if file doesn’t end with blank line
 	if inPackage && currentPkg != "" {
 		deps[currentPkg] = append(deps[currentPkg], path)
+		addVersion(versions, currentPkg, currentVersion)
 	}
 
 	return scanner.Err()
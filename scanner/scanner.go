@@ -3,11 +3,20 @@ package scanner
 type ScanOptions struct {
 	IncludeLockfiles bool
 	IncludeVendor    bool
+	IncludeImports   bool // also validate what source files import
 	Verbosity        int
+
+	// ResolveGoModule finds the module providing a Go package path named
+	// outside go.mod (imports, go install commands); without it such
+	// packages are kept by path
+	ResolveGoModule func(pkgPath string) (string, bool)
 }
 
 type DepMap = map[string][]string
 type AllDeps = map[string]DepMap
+
+// AllVersions maps ecosystem -> dependency -> versions pinned by the project
+type AllVersions = map[string]DepMap
 type LanguageScanner func(projectPath string, opts ScanOptions) (map[string][]string, error)
 
 func mergeDeps(dest, src DepMap) DepMap {
@@ -20,33 +29,128 @@ func mergeDeps(dest, src DepMap) DepMap {
 	return dest
 }
 
+// addVersion records a pinned version once per dependency
+func addVersion(versions DepMap, name, version string) {
+	if version == "" {
+		return
+	}
+	for _, v := range versions[name] {
+		if v == version {
+			return
+		}
+	}
+	versions[name] = append(versions[name], version)
+}
+
 // Main scan entrypoint
-func ScanDependencies(path string, opts ScanOptions) (AllDeps, error) {
+func ScanDependencies(path string, opts ScanOptions) (AllDeps, AllVersions, error) {
+	results, versions := ScanManifests(path, opts)
+
+	if aDeps, aVers, err := ScanActions(path, opts.IncludeVendor, opts.Verbosity); err == nil {
+		results["actions"] = mergeDeps(results["actions"], aDeps)
+		versions["actions"] = mergeDeps(versions["actions"], aVers)
+	}
+
+	if dDeps, dVers, err := ScanDockerfiles(path, opts.IncludeVendor, opts.Verbosity, opts.ResolveGoModule); err == nil {
+		for eco := range dDeps {
+			results[eco] = mergeDeps(results[eco], dDeps[eco])
+			versions[eco] = mergeDeps(versions[eco], dVers[eco])
+		}
+	}
+
+	if sDeps, sVers, err := ScanScripts(path, opts.IncludeVendor, opts.Verbosity, opts.ResolveGoModule); err == nil {
+		for eco := range sDeps {
+			results[eco] = mergeDeps(results[eco], sDeps[eco])
+			versions[eco] = mergeDeps(versions[eco], sVers[eco])
+		}
+	}
+
+	if nDeps, nVers, err := ScanNotebooks(path, opts.IncludeVendor, opts.Verbosity, opts.ResolveGoModule); err == nil {
+		for eco := range nDeps {
+			results[eco] = mergeDeps(results[eco], nDeps[eco])
+			versions[eco] = mergeDeps(versions[eco], nVers[eco])
+		}
+	}
+
+	if opts.IncludeImports {
+		imports, err := ScanImports(path, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		for eco, deps := range imports {
+			results[eco] = mergeDeps(results[eco], deps)
+		}
+	}
+
+	return results, versions, nil
+}
+
+// ScanManifests reads the dependencies a project's manifests (and, when
+// asked, lockfiles) declare, leaving out what Dockerfiles, workflows,
+// scripts and notebooks install
+func ScanManifests(path string, opts ScanOptions) (AllDeps, AllVersions) {
 	results := make(AllDeps)
+	versions := make(AllVersions)
 
-	if pDeps, err := ScanPython(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
+	if pDeps, pVers, err := ScanPython(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
 		results["pypi"] = mergeDeps(results["pypi"], pDeps)
+		versions["pypi"] = mergeDeps(versions["pypi"], pVers)
 	}
 
-	if nDeps, err := ScanJavaScript(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
+	if nDeps, nVers, err := ScanJavaScript(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
 		results["npm"] = mergeDeps(results["npm"], nDeps)
+		versions["npm"] = mergeDeps(versions["npm"], nVers)
 	}
 
-	if gDeps, err := ScanGo(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
+	if gDeps, gVers, err := ScanGo(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
 		results["go"] = mergeDeps(results["go"], gDeps)
+		versions["go"] = mergeDeps(versions["go"], gVers)
 	}
 
-	if phDeps, err := ScanPHP(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
+	if phDeps, phVers, err := ScanPHP(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
 		results["php"] = mergeDeps(results["php"], phDeps)
+		versions["php"] = mergeDeps(versions["php"], phVers)
 	}
 
-	if rDeps, err := ScanRuby(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
+	if rDeps, rVers, err := ScanRuby(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
 		results["ruby"] = mergeDeps(results["ruby"], rDeps)
+		versions["ruby"] = mergeDeps(versions["ruby"], rVers)
 	}
 
-	if ruDeps, err := ScanRust(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
+	if ruDeps, ruVers, err := ScanRust(path, opts.IncludeLockfiles, opts.IncludeVendor, opts.Verbosity); err == nil {
 		results["rust"] = mergeDeps(results["rust"], ruDeps)
+		versions["rust"] = mergeDeps(versions["rust"], ruVers)
+	}
+
+	return results, versions
+}
+
+// ScanImports finds the packages a project's source files import, with
+// "file:line" locations
+func ScanImports(path string, opts ScanOptions) (AllDeps, error) {
+	results := make(AllDeps)
+
+	pyDeps, err := ScanPythonImports(path, opts.IncludeVendor, opts.Verbosity)
+	if err != nil {
+		return nil, err
+	}
+	nbDeps, err := ScanNotebookImports(path, opts.IncludeVendor, opts.Verbosity)
+	if err != nil {
+		return nil, err
+	}
+	results["pypi"] = mergeDeps(pyDeps, nbDeps)
+
+	jsDeps, err := ScanJavaScriptImports(path, opts.IncludeVendor, opts.Verbosity)
+	if err != nil {
+		return nil, err
+	}
+	results["npm"] = jsDeps
+
+	goDeps, err := ScanGoImports(path, opts.IncludeVendor, opts.Verbosity, opts.ResolveGoModule)
+	if err != nil {
+		return nil, err
 	}
+	results["go"] = goDeps
 
 	return results, nil
 }
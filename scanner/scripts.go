@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// fencePattern matches the opening or closing line of a Markdown code block
+var fencePattern = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([\\w+-]*)")
+
+// shellFences are the code block languages holding commands; unlabelled
+// blocks usually do too
+var shellFences = toSet("", "sh", "bash", "shell", "zsh", "console", "shell-session", "shellsession", "terminal")
+
+// ScanScripts finds packages installed by name in shell scripts, Makefile
+// recipes and Markdown code blocks, keyed by ecosystem with "file:line"
+// locations. Downloads run straight in a shell (curl ... | sh) are recorded
+// under the "shell" ecosystem, keyed by URL. Go package paths are
+// attributed to their module with resolve when given.
+func ScanScripts(projectPath string, includeVendor bool, verbosity int, resolve func(string) (string, bool)) (AllDeps, AllVersions, error) {
+	if verbosity >= 2 {
+		fmt.Println("Scanning scripts and docs...")
+	}
+	deps := make(AllDeps)
+	versions := make(AllVersions)
+
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != projectPath && (info.Name() == ".git" || (!includeVendor && vendorDirs[info.Name()])) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if scriptExtractor(path) == nil {
+			return nil
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			parseScript(path, data, deps, versions, resolve)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if verbosity >= 2 {
+		count := 0
+		for _, names := range deps {
+			count += len(names)
+		}
+		fmt.Printf("Finished scanning scripts and docs... %d deps found\n\n", count)
+	}
+	return deps, versions, nil
+}
+
+// parseScript records what one script, Makefile or Markdown file installs
+func parseScript(path string, data []byte, deps AllDeps, versions AllVersions, resolve func(string) (string, bool)) {
+	script := scriptExtractor(path)(string(data))
+	addInstalls(deps, versions, lineLocation(path), shellInstalls(script, 1), resolve)
+	addRemoteScripts(deps, lineLocation(path), remoteScripts(script, 1))
+}
+
+// scriptExtractor picks how to get shell commands out of a file, or nil if
+// it holds none. Extractors blank out everything else, so line numbers in
+// the result are the file's.
+func scriptExtractor(path string) func(string) string {
+	base := filepath.Base(path)
+	switch {
+	case base == "Makefile" || base == "makefile" || base == "GNUmakefile" || filepath.Ext(base) == ".mk":
+		return makeRecipes
+	}
+	switch filepath.Ext(base) {
+	case ".sh", ".bash", ".zsh":
+		return func(s string) string { return s }
+	case ".md", ".markdown":
+		return markdownCommands
+	}
+	return nil
+}
+
+// makeRecipes keeps a Makefile's recipe lines, without the tab and the
+// @, - and + prefixes, and with $$ unescaped
+func makeRecipes(text string) string {
+	lines := strings.Split(text, "\n")
+	continued := false
+	for i, line := range lines {
+		isRecipe := strings.HasPrefix(line, "\t")
+		if !isRecipe && !continued {
+			lines[i] = ""
+			continue
+		}
+		if isRecipe && !continued {
+			line = strings.TrimLeft(strings.TrimPrefix(line, "\t"), "@-+ ")
+		}
+		continued = strings.HasSuffix(strings.TrimSpace(line), "\\")
+		lines[i] = strings.ReplaceAll(line, "$$", "$")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownCommands keeps the lines of shell code blocks, without any "$ "
+// prompt
+func markdownCommands(text string) string {
+	lines := strings.Split(text, "\n")
+	fence, keep := "", false
+	for i, line := range lines {
+		m := fencePattern.FindStringSubmatch(line)
+		switch {
+		case fence == "" && m != nil:
+			fence, keep = m[1], shellFences[strings.ToLower(m[2])]
+			lines[i] = ""
+		case fence != "" && m != nil && m[2] == "" && strings.HasPrefix(m[1], fence):
+			fence = ""
+			lines[i] = ""
+		case fence != "" && keep:
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "$ ") {
+				lines[i] = strings.TrimPrefix(trimmed, "$ ")
+			}
+		default:
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,292 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// shellCommand is one simple command from a shell script
+type shellCommand struct {
+	args []string
+	line int  // line of the command's first word
+	pipe bool // output goes to the next command
+}
+
+// scriptInstall is a package installed by a command in a script
+type scriptInstall struct {
+	InstallPackage
+	line int
+}
+
+// remoteScript is a downloaded script run straight in a shell
+type remoteScript struct {
+	source string // the URL, or the download command when there isn't one
+	line   int
+}
+
+// shellPrefixes run the command that follows them, or are keywords that
+// can precede one
+var shellPrefixes = toSet("sudo", "env", "exec", "command", "time", "nohup", "then", "do", "else", "if", "while", "until", "!", "{")
+
+// splitShell breaks a script into simple commands on newlines, ;, &&, ||,
+// pipes and subshells, honouring quotes, comments and line continuations.
+// It is no shell, but enough to find the package-manager commands in one.
+func splitShell(script string, firstLine int) []shellCommand {
+	var cmds []shellCommand
+	var cur shellCommand
+	var word strings.Builder
+	inWord := false
+	line := firstLine
+
+	startWord := func() {
+		if !inWord {
+			inWord = true
+			if len(cur.args) == 0 {
+				cur.line = line
+			}
+		}
+	}
+	endWord := func() {
+		if inWord {
+			cur.args = append(cur.args, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(cur.args) > 0 {
+			cmds = append(cmds, cur)
+		}
+		cur = shellCommand{}
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\\' && i+1 < len(script):
+			i++
+			if script[i] == '\n' {
+				line++
+				continue
+			}
+			startWord()
+			word.WriteByte(script[i])
+		case c == '\'':
+			startWord()
+			end := strings.IndexByte(script[i+1:], '\'')
+			if end < 0 {
+				end = len(script) - i - 1
+			}
+			text := script[i+1 : i+1+end]
+			word.WriteString(text)
+			line += strings.Count(text, "\n")
+			i += end + 1
+		case c == '"':
+			startWord()
+			for i++; i < len(script) && script[i] != '"'; i++ {
+				if script[i] == '\\' && i+1 < len(script) && strings.IndexByte("\"\\$`", script[i+1]) >= 0 {
+					i++
+				}
+				if script[i] == '\n' {
+					line++
+				}
+				word.WriteByte(script[i])
+			}
+		case c == '#' && !inWord:
+			for i+1 < len(script) && script[i+1] != '\n' {
+				i++
+			}
+		case c == '\n':
+			endCommand()
+			line++
+		case c == '|' && (i+1 == len(script) || script[i+1] != '|'):
+			cur.pipe = true
+			endCommand()
+		case strings.IndexByte(";&|()`", c) >= 0:
+			endCommand()
+		case c == ' ' || c == '\t' || c == '\r':
+			endWord()
+		default:
+			startWord()
+			word.WriteByte(c)
+		}
+	}
+	endCommand()
+	return cmds
+}
+
+// commandWords drops what precedes the program a command runs: variable
+// assignments, sudo and env, and shell keywords
+func commandWords(args []string) []string {
+	for len(args) > 0 {
+		switch a := args[0]; {
+		case shellPrefixes[a]:
+			args = args[1:]
+			for a == "sudo" && len(args) > 0 && strings.HasPrefix(args[0], "-") {
+				if args[0] == "-u" || args[0] == "-g" {
+					args = args[1:]
+				}
+				args = args[1:]
+			}
+		case isAssignment(a):
+			args = args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+// isAssignment spots NAME=value words
+func isAssignment(word string) bool {
+	name, _, found := strings.Cut(word, "=")
+	if !found || name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// shellInstalls finds the packages a script installs by name, including
+// in sh -c "..." scripts
+func shellInstalls(script string, firstLine int) []scriptInstall {
+	var installs []scriptInstall
+	for _, cmd := range splitShell(script, firstLine) {
+		args := commandWords(cmd.args)
+		if inner, ok := shellScriptArg(args); ok {
+			installs = append(installs, shellInstalls(inner, cmd.line)...)
+			continue
+		}
+		installs = append(installs, commandInstalls(args, cmd.line)...)
+	}
+	return installs
+}
+
+// remoteScripts finds downloads run straight in a shell: curl ... | sh,
+// bash <(curl ...) and sh -c "$(curl ...)"
+func remoteScripts(script string, firstLine int) []remoteScript {
+	var found []remoteScript
+	cmds := splitShell(script, firstLine)
+	for i, cmd := range cmds {
+		args := commandWords(cmd.args)
+		if len(args) == 0 || !isShell(args[0]) {
+			continue
+		}
+
+		if inner, ok := shellScriptArg(args); ok {
+			if strings.HasPrefix(inner, "$(") || strings.HasPrefix(inner, "`") {
+				substituted := splitShell(strings.Trim(inner, "$()`"), cmd.line)
+				if len(substituted) > 0 && isDownload(commandWords(substituted[0].args)) {
+					found = append(found, remoteScript{downloadSource(commandWords(substituted[0].args)), cmd.line})
+				}
+			}
+			found = append(found, remoteScripts(inner, cmd.line)...)
+			continue
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := cmds[i-1]
+		fetch := commandWords(prev.args)
+		// bash <(curl ...) splits into "bash <" and the download
+		substituted := i+1 < len(cmds) && args[len(args)-1] == "<"
+		if substituted {
+			fetch = commandWords(cmds[i+1].args)
+		}
+		if (prev.pipe || substituted) && isDownload(fetch) {
+			found = append(found, remoteScript{downloadSource(fetch), prev.line})
+		}
+	}
+	return found
+}
+
+// shellScriptArg returns the script of a sh -c "..." command
+func shellScriptArg(args []string) (string, bool) {
+	if len(args) >= 3 && isShell(args[0]) && args[1] == "-c" {
+		return args[2], true
+	}
+	return "", false
+}
+
+func isShell(program string) bool {
+	switch filepath.Base(program) {
+	case "sh", "bash", "ash", "dash", "zsh":
+		return true
+	}
+	return false
+}
+
+func isDownload(args []string) bool {
+	return len(args) > 0 && (args[0] == "curl" || args[0] == "wget")
+}
+
+// downloadSource picks the URL out of a curl or wget command
+func downloadSource(args []string) string {
+	for _, a := range args[1:] {
+		if strings.HasPrefix(a, "http://") || strings.HasPrefix(a, "https://") {
+			return a
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// commandInstalls finds the packages one command line installs
+func commandInstalls(args []string, line int) []scriptInstall {
+	var installs []scriptInstall
+	for _, pkg := range ParseInstallCommand(args) {
+		// names built from variables, or placeholders like <package>
+		if strings.ContainsAny(pkg.Name, "$<>{}") {
+			continue
+		}
+		if strings.Contains(pkg.Version, "$") {
+			pkg.Version = ""
+		}
+		installs = append(installs, scriptInstall{pkg, line})
+	}
+	return installs
+}
+
+// addInstalls records installed packages in a scan's results, located by
+// line. Go package paths are attributed to their module with resolve when
+// given.
+func addInstalls(deps AllDeps, versions AllVersions, locate func(line int) string, installs []scriptInstall, resolve func(string) (string, bool)) {
+	for _, in := range installs {
+		name := in.Name
+		if in.Ecosystem == "go" && resolve != nil {
+			if module, found := resolve(name); found {
+				name = module
+			}
+		}
+		if deps[in.Ecosystem] == nil {
+			deps[in.Ecosystem] = make(DepMap)
+			versions[in.Ecosystem] = make(DepMap)
+		}
+		deps[in.Ecosystem][name] = append(deps[in.Ecosystem][name], locate(in.line))
+		addVersion(versions[in.Ecosystem], name, in.Version)
+	}
+}
+
+// addRemoteScripts records downloads run straight in a shell under the
+// "shell" ecosystem, keyed by URL
+func addRemoteScripts(deps AllDeps, locate func(line int) string, scripts []remoteScript) {
+	for _, r := range scripts {
+		if deps["shell"] == nil {
+			deps["shell"] = make(DepMap)
+		}
+		deps["shell"][r.source] = append(deps["shell"][r.source], locate(r.line))
+	}
+}
+
+// lineLocation locates lines of a file as "file:line"
+func lineLocation(path string) func(int) string {
+	return func(line int) string {
+		return fmt.Sprintf("%s:%d", path, line)
+	}
+}
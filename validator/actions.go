@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/utils"
+)
+
+// GitHubAPI is the API actions are looked up in. GitHub Enterprise Server
+// serves it at https://<host>/api/v3.
+var GitHubAPI = "https://api.github.com"
+
+// commitSHA matches a ref pinned to a full commit hash
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+type githubRepo struct {
+	CreatedAt time.Time `json:"created_at"`
+	License   struct {
+		SPDXID string `json:"spdx_id"`
+	} `json:"license"`
+}
+
+// validateAction checks that an action's repository exists and isn't brand
+// new, and that each ref it is used at exists. Refs other than a full
+// commit SHA can be moved to new code by whoever controls the repository,
+// so they are flagged too. An action the API rate limit kept from being
+// checked is left for investigation, never passed as safe.
+func validateAction(name string, paths, refs []string) ValidationResult {
+	result := ValidationResult{Name: name, Source: "actions", Paths: paths, Repository: "https://github.com/" + name}
+
+	var repo githubRepo
+	status, err := githubGet("/repos/"+name, &repo)
+	switch {
+	case err != nil:
+		result.Status = "investigate"
+		result.Details = fmt.Sprintf("GitHub API unreachable: %v", err)
+		return result
+	case status == 404:
+		result.Status = "not_found"
+		result.Details = "Repository not found on GitHub"
+		return result
+	case status == 403 || status == 429:
+		result.Status = "investigate"
+		result.Details = "Not checked: GitHub API rate limit reached (set GITHUB_TOKEN to raise it)"
+		checkRefs(&result, name, refs, false)
+		return result
+	case status != 200:
+		result.Status = "investigate"
+		result.Details = fmt.Sprintf("GitHub API returned %d", status)
+		return result
+	}
+
+	if repo.License.SPDXID != "NOASSERTION" {
+		result.License = repo.License.SPDXID
+	}
+	result.Metadata.Created = repo.CreatedAt
+
+	age := time.Since(repo.CreatedAt)
+	if age < NewPackageAge {
+		result.Status = "investigate"
+		result.Details = fmt.Sprintf("Recently created repository (%s)", utils.HumanDuration(age))
+	} else {
+		result.Status = "safe"
+		result.Details = "-"
+	}
+
+	checkRefs(&result, name, refs, true)
+
+	return result
+}
+
+// checkRefs flags the refs an action is used at that don't exist, when
+// lookup is set, and those that aren't a full commit SHA
+func checkRefs(result *ValidationResult, name string, refs []string, lookup bool) {
+	for _, ref := range refs {
+		if lookup {
+			// tags and branches may contain slashes, which the endpoint takes as is
+			escaped := strings.Split(ref, "/")
+			for i := range escaped {
+				escaped[i] = url.PathEscape(escaped[i])
+			}
+			if status, err := githubGet("/repos/"+name+"/commits/"+strings.Join(escaped, "/"), nil); err == nil && (status == 404 || status == 422) {
+				result.addFinding(Finding{Type: "ref_not_found", Details: fmt.Sprintf("Ref %s not found", ref)})
+				continue
+			}
+		}
+		if !commitSHA.MatchString(ref) {
+			result.addFinding(Finding{Type: "ref_unpinned", Details: fmt.Sprintf("Ref %s is not pinned to a commit SHA", ref)})
+		}
+	}
+}
+
+// githubGet requests an API path, decoding a successful response into v
+// when given, and returns the status code
+func githubGet(path string, v any) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(GitHubAPI, "/")+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := registryClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 200 && v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return 0, err
+		}
+	}
+	return resp.StatusCode, nil
+}
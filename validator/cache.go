@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache remembers registry lookups so long-running servers don't repeat
+// them for every request naming the same package
+type Cache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type cacheEntry struct {
+	result  ValidationResult
+	expires time.Time
+}
+
+// NewCache returns a cache whose entries live for ttl
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: map[string]cacheEntry{}}
+}
+
+// ValidatePackage is ValidatePackage backed by the cache. Only the registry
+// lookup is cached; paths and versions are the caller's. An action's
+// findings depend on the refs it is used at, so those are cached by ref.
+func (c *Cache) ValidatePackage(eco, pkg string, paths, versions []string) (ValidationResult, bool) {
+	key := eco + ":" + pkg
+	if eco == "actions" {
+		refs := append([]string(nil), versions...)
+		sort.Strings(refs)
+		key += "@" + strings.Join(refs, ",")
+	}
+
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+	if found && time.Now().Before(entry.expires) {
+		c.hits.Add(1)
+		result := entry.result
+		result.Paths = paths
+		result.Versions = versions
+		return result, true
+	}
+
+	c.misses.Add(1)
+	result, ok := ValidatePackage(eco, pkg, paths, versions)
+	if !ok || result.Unreachable {
+		return result, ok
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{result: result, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return result, true
+}
+
+// ValidatePackages is ValidatePackages backed by the cache
+func (c *Cache) ValidatePackages(allDeps, allVersions map[string]map[string][]string) []ValidationResult {
+	var results []ValidationResult
+
+	for eco, deps := range allDeps {
+		for pkg, paths := range deps {
+			if result, ok := c.ValidatePackage(eco, pkg, paths, allVersions[eco][pkg]); ok {
+				results = append(results, result)
+			}
+		}
+	}
+
+	return results
+}
+
+// Stats returns how many lookups were answered from the cache and how
+// many went to a registry
+func (c *Cache) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
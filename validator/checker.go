@@ -1,35 +1,126 @@
 package validator
 
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// registryClient bounds every registry lookup so a hung registry can't
+// stall a scan
+var registryClient = &http.Client{Timeout: 30 * time.Second}
+
 // ValidationResult holds dependency check results with multiple paths
 type ValidationResult struct {
-	Name    string
-	Source  string // "npm", "pypi", "go"
-	Status  string // "safe", "investigate", "not_found"
-	Details string
-	Paths   []string `json:"paths"`
+	Name       string    `json:"name"`
+	Source     string    `json:"source"` // "npm", "pypi", "go"
+	Status     string    `json:"status"` // "safe", "investigate", "not_found"
+	Details    string    `json:"details"`
+	Paths      []string  `json:"paths"`
+	Versions   []string  `json:"versions,omitempty"`
+	Repository string    `json:"repository,omitempty"`
+	License    string    `json:"license,omitempty"`
+	Metadata   Metadata  `json:"metadata"`
+	Findings   []Finding `json:"findings,omitempty"`
+	Score      float64   `json:"score"`
+	Signals    []Signal  `json:"signals,omitempty"`
+	Change     string    `json:"change,omitempty"` // set in diff mode: "added" or "version changed (...)"
+
+	// Unreachable is set when the registry couldn't be asked, so the verdict
+	// says nothing about the package and shouldn't be cached
+	Unreachable bool `json:"-"`
+}
+
+// Metadata holds the registry facts behind a result, beyond existence
+type Metadata struct {
+	Created        time.Time `json:"created,omitempty"`
+	LatestVersion  string    `json:"latest_version,omitempty"`
+	LatestRelease  time.Time `json:"latest_release,omitempty"`
+	Downloads      int64     `json:"downloads,omitempty"`
+	DownloadPeriod string    `json:"download_period,omitempty"` // "week" or "total"; empty when the registry doesn't say
+	InstallScripts []string  `json:"install_scripts,omitempty"`
+	NewPublisher   string    `json:"new_publisher,omitempty"` // set when the latest release came from someone new
+}
+
+// Finding is an extra signal raised against a dependency after the registry
+// lookup, e.g. a repository link that goes nowhere
+type Finding struct {
+	Type      string `json:"type"`
+	Details   string `json:"details"`
+	Severity  string `json:"severity,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// addFinding records a finding and escalates a safe result to investigate
+func (r *ValidationResult) addFinding(f Finding) {
+	r.Findings = append(r.Findings, f)
+	if r.Status == "safe" {
+		r.Status = "investigate"
+	}
 }
 
-func ValidatePackages(allDeps map[string]map[string][]string) []ValidationResult {
+func ValidatePackages(allDeps, allVersions map[string]map[string][]string) []ValidationResult {
 	var results []ValidationResult
 
 	for eco, deps := range allDeps {
 		for pkg, paths := range deps {
-			switch eco {
-			case "pypi":
-				results = append(results, validatePyPI(pkg, paths))
-			case "npm":
-				results = append(results, validateNPM(pkg, paths))
-			case "go":
-				results = append(results, validateGoModule(pkg, paths))
-			case "php":
-				results = append(results, validatePHP(pkg, paths))
-			case "ruby":
-				results = append(results, validateRuby(pkg, paths))
-			case "rust":
-				results = append(results, validateRust(pkg, paths))
+			if result, ok := ValidatePackage(eco, pkg, paths, allVersions[eco][pkg]); ok {
+				results = append(results, result)
 			}
 		}
 	}
 
 	return results
 }
+
+// ValidatePackage looks up a single package, reporting false for an
+// ecosystem there is no validator for
+func ValidatePackage(eco, pkg string, paths, versions []string) (ValidationResult, bool) {
+	var result ValidationResult
+	switch eco {
+	case "pypi":
+		result = validatePyPI(pkg, paths)
+	case "npm":
+		result = validateNPM(pkg, paths)
+	case "go":
+		result = validateGoModule(pkg, paths)
+	case "php":
+		result = validatePHP(pkg, paths)
+	case "ruby":
+		result = validateRuby(pkg, paths)
+	case "rust":
+		result = validateRust(pkg, paths)
+	case "actions":
+		result = validateAction(pkg, paths, versions)
+	case "shell":
+		result = validateRemoteScript(pkg, paths)
+	default:
+		return result, false
+	}
+	result.Versions = versions
+	return result, true
+}
+
+// registryGet fetches a registry document. Only a 200 or a 404/410 counts as
+// an answer; anything else is an error so the package isn't reported missing
+// because the registry was down.
+func registryGet(url string) (*http.Response, error) {
+	resp, err := registryClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNotFound, http.StatusGone:
+		return resp, nil
+	}
+	resp.Body.Close()
+	return nil, fmt.Errorf("returned %s", resp.Status)
+}
+
+// unreachable marks result as unchecked because registry couldn't be reached
+func unreachable(result ValidationResult, registry string, err error) ValidationResult {
+	result.Status = "investigate"
+	result.Details = fmt.Sprintf("Not checked: %s unreachable (%v)", registry, err)
+	result.Unreachable = true
+	return result
+}
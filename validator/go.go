@@ -2,13 +2,23 @@ package validator
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/Kelcode-Dev/vibe-validator/utils"
+	gomodule "golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
+const goProxyURL = "https://proxy.golang.org"
+
+// errGoNotFound is the Go proxy answering that it has no such module or
+// version, as opposed to not answering at all
+var errGoNotFound = errors.New("not found")
+
 type goModuleInfo struct {
 	Version string    `json:"Version"`
 	Time    time.Time `json:"Time"`
@@ -17,30 +27,124 @@ type goModuleInfo struct {
 func validateGoModule(module string, paths []string) ValidationResult {
 	result := ValidationResult{Name: module, Source: "go", Paths: paths}
 
-	url := fmt.Sprintf("https://proxy.golang.org/%s/@latest", module)
-	resp, err := http.Get(url)
-	if err != nil || resp == nil || resp.StatusCode != 200 {
+	escaped, err := gomodule.EscapePath(module)
+	if err != nil {
 		result.Status = "not_found"
-		result.Details = "Not found in Go proxy"
+		result.Details = "Invalid module path"
 		return result
 	}
-	defer resp.Body.Close()
 
-	var info goModuleInfo
-	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		result.Status = "unknown"
-		result.Details = "Unable to parse module metadata"
+	// @latest only tells us when the newest version was tagged, so age the
+	// module by its earliest listed release and use the newest for recency
+	var first, latest goModuleInfo
+	versions, err := fetchGoVersions(escaped)
+	if err == nil && len(versions) > 0 {
+		semver.Sort(versions)
+		first, err = fetchGoInfo(escaped + "/@v/" + versions[0] + ".info")
+		if err == nil {
+			latest, err = fetchGoInfo(escaped + "/@v/" + versions[len(versions)-1] + ".info")
+		}
+		// the module exists, so a missing version isn't a missing module
+		if errors.Is(err, errGoNotFound) {
+			result.Status = "investigate"
+			result.Details = fmt.Sprintf("Listed, but version info is missing from Go proxy (%v)", err)
+			return result
+		}
+	} else {
+		// modules with no tags only resolve to a pseudo-version via @latest
+		latest, err = fetchGoInfo(escaped + "/@latest")
+		first = latest
+	}
+	if errors.Is(err, errGoNotFound) {
+		result.Status = "not_found"
+		result.Details = "Not found in Go proxy"
 		return result
 	}
+	if err != nil {
+		return unreachable(result, "Go proxy", err)
+	}
+
+	result.Metadata.Created = first.Time
+	result.Metadata.LatestVersion = latest.Version
+	result.Metadata.LatestRelease = latest.Time
 
-	age := time.Since(info.Time)
-	if age < 30*24*time.Hour {
+	age := time.Since(first.Time)
+	if age < NewPackageAge {
 		result.Status = "investigate"
 		result.Details = fmt.Sprintf("Recently added (%s)", utils.HumanDuration(age))
-	} else {
-		result.Status = "safe"
-		result.Details = "-"
+		return result
+	}
+
+	result.Status = "safe"
+	result.Details = "-"
+	if since := time.Since(latest.Time); since < NewPackageAge {
+		result.Details = fmt.Sprintf("Latest release %s (%s)", latest.Version, utils.HumanDuration(since))
 	}
 
 	return result
 }
+
+// fetchGoInfo decodes a version info document from the Go proxy
+func fetchGoInfo(endpoint string) (goModuleInfo, error) {
+	var info goModuleInfo
+
+	resp, err := registryGet(fmt.Sprintf("%s/%s", goProxyURL, endpoint))
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return info, fmt.Errorf("%s: %w", endpoint, errGoNotFound)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// fetchGoVersions returns the tagged versions the Go proxy knows about
+func fetchGoVersions(escaped string) ([]string, error) {
+	resp, err := registryGet(fmt.Sprintf("%s/%s/@v/list", goProxyURL, escaped))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, errGoNotFound
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, v := range strings.Fields(string(data)) {
+		if semver.IsValid(v) {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// ResolveGoModule finds the module providing a package path by trimming
+// trailing elements until the Go proxy recognises one, e.g.
+// github.com/spf13/cobra/doc resolves to github.com/spf13/cobra
+func ResolveGoModule(pkg string) (string, bool) {
+	for path := pkg; strings.Contains(path, "/"); path = path[:strings.LastIndex(path, "/")] {
+		escaped, err := gomodule.EscapePath(path)
+		if err != nil {
+			continue
+		}
+		if versions, err := fetchGoVersions(escaped); err == nil && len(versions) > 0 {
+			return path, true
+		}
+		if _, err := fetchGoInfo(escaped + "/@latest"); err == nil {
+			return path, true
+		}
+	}
+	return pkg, false
+}
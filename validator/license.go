@@ -0,0 +1,322 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+)
+
+const depsDevURL = "https://api.deps.dev/v3"
+
+// spdxIDs are the SPDX identifiers we expect to meet in registry metadata,
+// keyed by lower case so lookups can fix capitalisation
+var spdxIDs = map[string]string{}
+
+func init() {
+	for _, id := range []string{
+		"0BSD", "AFL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0",
+		"Artistic-1.0", "Artistic-2.0", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause",
+		"BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "CC-BY-3.0",
+		"CC-BY-4.0", "CC-BY-SA-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1", "EPL-1.0", "EPL-2.0",
+		"EUPL-1.1", "EUPL-1.2", "GPL-1.0-only", "GPL-1.0-or-later", "GPL-2.0-only",
+		"GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later", "HPND", "ISC", "LGPL-2.0-only",
+		"LGPL-2.0-or-later", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0-only",
+		"LGPL-3.0-or-later", "MIT", "MIT-0", "MPL-1.1", "MPL-2.0", "MS-PL", "NCSA", "OFL-1.1",
+		"OpenSSL", "PHP-3.0", "PHP-3.01", "PostgreSQL", "PSF-2.0", "Python-2.0", "Ruby",
+		"SSPL-1.0", "Unicode-3.0", "Unicode-DFS-2016", "Unlicense", "UPL-1.0", "W3C", "WTFPL",
+		"X11", "Zlib", "ZPL-2.1",
+	} {
+		spdxIDs[strings.ToLower(id)] = id
+	}
+}
+
+// licenseAliases maps the free text found in older metadata (and deprecated
+// SPDX ids) to an SPDX identifier
+var licenseAliases = map[string]string{
+	"mit license":                           "MIT",
+	"the mit license":                       "MIT",
+	"expat":                                 "MIT",
+	"apache 2":                              "Apache-2.0",
+	"apache 2.0":                            "Apache-2.0",
+	"apache-2":                              "Apache-2.0",
+	"apache license 2.0":                    "Apache-2.0",
+	"apache license, version 2.0":           "Apache-2.0",
+	"apache software license":               "Apache-2.0",
+	"apache software license 2.0":           "Apache-2.0",
+	"isc license":                           "ISC",
+	"isc license (iscl)":                    "ISC",
+	"bsd-2":                                 "BSD-2-Clause",
+	"bsd-3":                                 "BSD-3-Clause",
+	"new bsd":                               "BSD-3-Clause",
+	"new bsd license":                       "BSD-3-Clause",
+	"simplified bsd":                        "BSD-2-Clause",
+	"gpl-2.0":                               "GPL-2.0-only",
+	"gpl-2.0+":                              "GPL-2.0-or-later",
+	"gplv2":                                 "GPL-2.0-only",
+	"gplv2+":                                "GPL-2.0-or-later",
+	"gpl-3.0":                               "GPL-3.0-only",
+	"gpl-3.0+":                              "GPL-3.0-or-later",
+	"gplv3":                                 "GPL-3.0-only",
+	"gplv3+":                                "GPL-3.0-or-later",
+	"gnu general public license v2 (gplv2)": "GPL-2.0-only",
+	"gnu general public license v3 (gplv3)": "GPL-3.0-only",
+	"gnu general public license v3 or later (gplv3+)": "GPL-3.0-or-later",
+	"lgpl-2.1":  "LGPL-2.1-only",
+	"lgpl-2.1+": "LGPL-2.1-or-later",
+	"lgpl-3.0":  "LGPL-3.0-only",
+	"lgpl-3.0+": "LGPL-3.0-or-later",
+	"lgplv3":    "LGPL-3.0-only",
+	"gnu lesser general public license v3 (lgplv3)": "LGPL-3.0-only",
+	"agpl-3.0":                             "AGPL-3.0-only",
+	"agpl-3.0+":                            "AGPL-3.0-or-later",
+	"agplv3":                               "AGPL-3.0-only",
+	"gnu affero general public license v3": "AGPL-3.0-only",
+	"gnu affero general public license v3 or later (agplv3+)": "AGPL-3.0-or-later",
+	"mozilla public license 2.0 (mpl 2.0)":                    "MPL-2.0",
+	"mpl 2.0":                                                 "MPL-2.0",
+	"the unlicense":                                           "Unlicense",
+	"the unlicense (unlicense)":                               "Unlicense",
+	"cc0":                                                     "CC0-1.0",
+	"cc0 1.0 universal (cc0 1.0) public domain dedication": "CC0-1.0",
+	"python software foundation license":                   "PSF-2.0",
+	"psf":                                                  "PSF-2.0",
+	"zlib license":                                         "Zlib",
+	"zlib/libpng license":                                  "Zlib",
+	"boost software license 1.0 (bsl-1.0)":                 "BSL-1.0",
+	"eclipse public license 2.0 (epl-2.0)":                 "EPL-2.0",
+}
+
+// CheckLicenses normalizes each dependency's license to an SPDX expression
+// and applies the license policy. License findings don't change a
+// dependency's status; the report lists them in their own section.
+func CheckLicenses(results []ValidationResult, policy config.License) {
+	for i := range results {
+		r := &results[i]
+		if r.Status == "not_found" {
+			continue
+		}
+
+		if r.Source == "go" && r.License == "" && len(r.Versions) > 0 {
+			r.License = fetchGoLicense(r.Name, r.Versions[0])
+		}
+
+		if strings.TrimSpace(r.License) == "" {
+			r.Findings = append(r.Findings, Finding{Type: "license_missing", Details: "No license declared"})
+			continue
+		}
+
+		expr, alternatives, known := normalizeLicense(r.License)
+		r.License = expr
+
+		allowed, denied := evaluateLicense(alternatives, policy)
+		switch {
+		case allowed:
+		case denied != "":
+			r.Findings = append(r.Findings, Finding{Type: "license_denied", Details: fmt.Sprintf("%s is denied by policy", denied)})
+		case !known:
+			r.Findings = append(r.Findings, Finding{Type: "license_unknown", Details: fmt.Sprintf("%q is not a recognised SPDX license", expr)})
+		default:
+			r.Findings = append(r.Findings, Finding{Type: "license_not_allowed", Details: fmt.Sprintf("%s is not on the allow list", expr)})
+		}
+	}
+}
+
+// evaluateLicense reports whether any alternative satisfies the policy and,
+// if every alternative is ruled out by a deny entry, the first denied license
+func evaluateLicense(alternatives [][]string, policy config.License) (bool, string) {
+	var denied string
+	allDenied := true
+
+	for _, alt := range alternatives {
+		ok := true
+		altDenied := false
+		for _, id := range alt {
+			if matchesLicense(id, policy.Deny) {
+				ok = false
+				altDenied = true
+				if denied == "" {
+					denied = id
+				}
+			} else if len(policy.Allow) > 0 && !matchesLicense(id, policy.Allow) {
+				ok = false
+			} else if _, known := spdxIDs[strings.ToLower(licenseBase(id))]; !known && len(policy.Allow) == 0 {
+				ok = false
+			}
+		}
+		if ok {
+			return true, ""
+		}
+		if !altDenied {
+			allDenied = false
+		}
+	}
+
+	if allDenied {
+		return false, denied
+	}
+	return false, ""
+}
+
+func matchesLicense(id string, patterns []string) bool {
+	id = strings.ToLower(licenseBase(id))
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		switch {
+		case strings.HasSuffix(p, "*"):
+			if strings.HasPrefix(id, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		case id == p, strings.TrimSuffix(strings.TrimSuffix(id, "-only"), "-or-later") == p:
+			return true
+		}
+	}
+	return false
+}
+
+// licenseBase drops a "WITH exception" clause
+func licenseBase(id string) string {
+	base, _, _ := strings.Cut(id, " WITH ")
+	return base
+}
+
+// normalizeLicense turns registry license text into an SPDX expression and
+// its alternatives (OR branches, each a set of licenses that all apply),
+// reporting whether every license in it is a known SPDX identifier
+func normalizeLicense(raw string) (string, [][]string, bool) {
+	raw = strings.TrimSpace(raw)
+	if id, ok := lookupLicense(raw); ok {
+		return id, [][]string{{id}}, true
+	}
+
+	p := &licenseParser{tokens: tokenizeLicense(raw), known: true}
+	alternatives := p.parseOr()
+	if p.pos < len(p.tokens) || len(alternatives) == 0 {
+		// not an expression we understand; treat it as one opaque license
+		return raw, [][]string{{raw}}, false
+	}
+	return p.expr.String(), alternatives, p.known
+}
+
+func lookupLicense(s string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	if id, ok := spdxIDs[key]; ok {
+		return id, true
+	}
+	if id, ok := licenseAliases[key]; ok {
+		return id, true
+	}
+	if id, ok := licenseAliases[strings.TrimSuffix(key, " license")]; ok {
+		return id, true
+	}
+	return "", false
+}
+
+func tokenizeLicense(s string) []string {
+	// crates.io used to separate alternatives with "/"
+	s = strings.ReplaceAll(s, "/", " OR ")
+	s = strings.ReplaceAll(s, "(", " ( ")
+	s = strings.ReplaceAll(s, ")", " ) ")
+	return strings.Fields(s)
+}
+
+type licenseParser struct {
+	tokens []string
+	pos    int
+	known  bool
+	expr   strings.Builder
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *licenseParser) parseOr() [][]string {
+	alternatives := p.parseAnd()
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		p.expr.WriteString(" OR ")
+		alternatives = append(alternatives, p.parseAnd()...)
+	}
+	return alternatives
+}
+
+func (p *licenseParser) parseAnd() [][]string {
+	alternatives := p.parseTerm()
+	for strings.EqualFold(p.peek(), "AND") {
+		p.pos++
+		p.expr.WriteString(" AND ")
+		right := p.parseTerm()
+
+		// distribute: (a OR b) AND c => (a AND c) OR (b AND c)
+		var combined [][]string
+		for _, l := range alternatives {
+			for _, r := range right {
+				combined = append(combined, append(append([]string{}, l...), r...))
+			}
+		}
+		alternatives = combined
+	}
+	return alternatives
+}
+
+func (p *licenseParser) parseTerm() [][]string {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil
+	case tok == "(":
+		p.pos++
+		p.expr.WriteString("(")
+		alternatives := p.parseOr()
+		if p.peek() == ")" {
+			p.pos++
+			p.expr.WriteString(")")
+		}
+		return alternatives
+	case tok == ")", strings.EqualFold(tok, "AND"), strings.EqualFold(tok, "OR"):
+		return nil
+	}
+
+	p.pos++
+	id, ok := lookupLicense(tok)
+	if !ok {
+		// includes LicenseRef- custom licenses, which need a human to look at
+		id = tok
+		p.known = false
+	}
+	if strings.EqualFold(p.peek(), "WITH") && p.pos+1 < len(p.tokens) {
+		id += " WITH " + p.tokens[p.pos+1]
+		p.pos += 2
+	}
+	p.expr.WriteString(id)
+	return [][]string{{id}}
+}
+
+// fetchGoLicense asks deps.dev for a module's license, as the Go proxy
+// doesn't carry any license metadata
+func fetchGoLicense(module, version string) string {
+	endpoint := fmt.Sprintf("%s/systems/go/packages/%s/versions/%s", depsDevURL, url.PathEscape(module), url.PathEscape(version))
+	resp, err := registryClient.Get(endpoint)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return ""
+	}
+
+	var data struct {
+		Licenses []string `json:"licenses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return ""
+	}
+	return strings.Join(data.Licenses, " AND ")
+}
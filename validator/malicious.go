@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+)
+
+// Blocklist maps ecosystem -> normalized package name -> reason it is blocked
+type Blocklist map[string]map[string]string
+
+// LoadBlocklist reads a blocklist file with one "ecosystem:name" per line,
+// optionally followed by a "# reason" comment
+func LoadBlocklist(path string) (Blocklist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	blocklist := make(Blocklist)
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line, reason, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		eco, name, ok := strings.Cut(line, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s:%d: expected ecosystem:name", path, lineNo)
+		}
+		if _, known := osv.Ecosystems[eco]; !known {
+			return nil, fmt.Errorf("%s:%d: unknown ecosystem %q", path, lineNo, eco)
+		}
+
+		if blocklist[eco] == nil {
+			blocklist[eco] = map[string]string{}
+		}
+		blocklist[eco][osv.NormalizeName(eco, name)] = strings.TrimSpace(reason)
+	}
+
+	return blocklist, scanner.Err()
+}
+
+// CheckMalicious marks dependencies that appear in a malicious-packages feed
+// or the blocklist. Only the feed's OSV MAL- entries count, so an OSV.dev
+// export with vulnerabilities in it too can be used as the feed. A match
+// outranks every other status, even when the registry has since taken the
+// package down.
+func CheckMalicious(results []ValidationResult, db *osv.DB, blocklist Blocklist) {
+	for i := range results {
+		r := &results[i]
+
+		if reason, found := blocklist[r.Source][osv.NormalizeName(r.Source, r.Name)]; found {
+			details := "Blocklisted"
+			if reason != "" {
+				details += ": " + reason
+			}
+			r.markMalicious(Finding{Type: "malicious", Details: details, Reference: "blocklist"})
+		}
+
+		if db == nil {
+			continue
+		}
+
+		var advisories []osv.Advisory
+		if len(r.Versions) == 0 {
+			advisories = db.QueryAnyVersion(r.Source, r.Name)
+		}
+		for _, version := range r.Versions {
+			advisories = append(advisories, db.Query(r.Source, r.Name, version)...)
+		}
+
+		seen := map[string]bool{}
+		for _, adv := range advisories {
+			if seen[adv.ID] || !adv.Malicious() {
+				continue
+			}
+			seen[adv.ID] = true
+
+			details := fmt.Sprintf("Known malicious package (%s)", adv.ID)
+			if adv.Summary != "" {
+				details += ": " + adv.Summary
+			}
+			r.markMalicious(Finding{Type: "malicious", Details: details, Reference: adv.ID})
+		}
+	}
+}
+
+func (r *ValidationResult) markMalicious(f Finding) {
+	r.Findings = append(r.Findings, f)
+	r.Status = "malicious"
+}
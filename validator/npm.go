@@ -3,27 +3,80 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Kelcode-Dev/vibe-validator/utils"
 )
 
 type npmMetadata struct {
-	Time map[string]string `json:"time"`
+	Time       map[string]string `json:"time"`
+	Repository json.RawMessage   `json:"repository"`
+	License    json.RawMessage   `json:"license"`
+	DistTags   struct {
+		Latest string `json:"latest"`
+	} `json:"dist-tags"`
+	Versions map[string]struct {
+		Scripts map[string]string `json:"scripts"`
+		NpmUser struct {
+			Name string `json:"name"`
+		} `json:"_npmUser"`
+	} `json:"versions"`
+}
+
+// NPMRegistry is the registry npm packages are looked up in
+var NPMRegistry = "https://registry.npmjs.org"
+
+// installScripts are the lifecycle hooks npm runs on install
+var installScripts = []string{"preinstall", "install", "postinstall"}
+
+// repositoryURL returns the repository link, which npm allows to be either
+// a plain string or an object with a url field
+func (m npmMetadata) repositoryURL() string {
+	var url string
+	if err := json.Unmarshal(m.Repository, &url); err == nil {
+		return url
+	}
+	var repo struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(m.Repository, &repo); err == nil {
+		return repo.URL
+	}
+	return ""
+}
+
+// licenseName returns the license, which old packages give as an object
+// with a type field instead of an SPDX string
+func (m npmMetadata) licenseName() string {
+	var license string
+	if err := json.Unmarshal(m.License, &license); err == nil {
+		return license
+	}
+	var legacy struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(m.License, &legacy); err == nil {
+		return legacy.Type
+	}
+	return ""
 }
 
 func validateNPM(packageName string, paths []string) ValidationResult {
 	result := ValidationResult{Name: packageName, Source: "npm", Paths: paths}
 
-	url := fmt.Sprintf("https://registry.npmjs.org/%s", packageName)
-	resp, err := http.Get(url)
-	if err != nil || resp == nil || resp.StatusCode != 200 {
+	url := fmt.Sprintf("%s/%s", strings.TrimSuffix(NPMRegistry, "/"), packageName)
+	resp, err := registryGet(url)
+	if err != nil {
+		return unreachable(result, "npm", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
 		result.Status = "not_found"
 		result.Details = "Not found on npm"
 		return result
 	}
-	defer resp.Body.Close()
 
 	var data npmMetadata
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -32,6 +85,11 @@ func validateNPM(packageName string, paths []string) ValidationResult {
 		return result
 	}
 
+	result.Repository = data.repositoryURL()
+	result.License = data.licenseName()
+	data.fillMetadata(&result.Metadata)
+	result.Metadata.Downloads, result.Metadata.DownloadPeriod = fetchNPMDownloads(packageName)
+
 	createdAt := data.Time["created"]
 	t, err := time.Parse(time.RFC3339, createdAt)
 	if err != nil {
@@ -39,9 +97,10 @@ func validateNPM(packageName string, paths []string) ValidationResult {
 		result.Details = "Invalid publish timestamp"
 		return result
 	}
+	result.Metadata.Created = t
 
 	age := time.Since(t)
-	if age < 30*24*time.Hour {
+	if age < NewPackageAge {
 		result.Status = "investigate"
 		result.Details = fmt.Sprintf("Very new package (published %s ago)", utils.HumanDuration(age))
 	} else {
@@ -51,3 +110,59 @@ func validateNPM(packageName string, paths []string) ValidationResult {
 
 	return result
 }
+
+// fillMetadata records the latest release, its install hooks and whether it
+// was published by someone who never published an earlier version
+func (m npmMetadata) fillMetadata(meta *Metadata) {
+	latest := m.DistTags.Latest
+	meta.LatestVersion = latest
+	if t, err := time.Parse(time.RFC3339, m.Time[latest]); err == nil {
+		meta.LatestRelease = t
+	}
+
+	release, ok := m.Versions[latest]
+	if !ok {
+		return
+	}
+	for _, hook := range installScripts {
+		if _, found := release.Scripts[hook]; found {
+			meta.InstallScripts = append(meta.InstallScripts, hook)
+		}
+	}
+
+	publisher := release.NpmUser.Name
+	earlier := 0
+	for version, v := range m.Versions {
+		if version == latest || v.NpmUser.Name == "" {
+			continue
+		}
+		earlier++
+		if v.NpmUser.Name == publisher {
+			return
+		}
+	}
+	if publisher != "" && earlier > 0 {
+		meta.NewPublisher = publisher
+	}
+}
+
+// fetchNPMDownloads returns last week's download count
+func fetchNPMDownloads(packageName string) (int64, string) {
+	resp, err := registryClient.Get(fmt.Sprintf("https://api.npmjs.org/downloads/point/last-week/%s", packageName))
+	if err != nil {
+		return 0, ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return 0, ""
+	}
+
+	var data struct {
+		Downloads int64 `json:"downloads"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return 0, ""
+	}
+	return data.Downloads, "week"
+}
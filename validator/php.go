@@ -3,13 +3,15 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 )
 
 type packagistResponse struct {
 	Packages map[string][]struct {
-		Time string `json:"time"` // ISO8601 timestamp of the version release
+		Version string   `json:"version"`
+		Time    string   `json:"time"` // ISO8601 timestamp of the version release
+		License []string `json:"license"`
 	} `json:"packages"`
 }
 
@@ -21,13 +23,17 @@ func validatePHP(packageName string, paths []string) ValidationResult {
 	}
 
 	url := fmt.Sprintf("https://repo.packagist.org/p/%s.json", packageName)
-	resp, err := http.Get(url)
-	if err != nil || resp == nil || resp.StatusCode != 200 {
+	resp, err := registryGet(url)
+	if err != nil {
+		return unreachable(result, "Packagist", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
 		result.Status = "not_found"
 		result.Details = "Not found on Packagist"
 		return result
 	}
-	defer resp.Body.Close()
 
 	var data packagistResponse
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -43,6 +49,9 @@ func validatePHP(packageName string, paths []string) ValidationResult {
 		return result
 	}
 
+	// newest release comes first
+	result.License = strings.Join(versions[0].License, " OR ")
+
 	// Find oldest release time
 	var oldest time.Time
 	for _, v := range versions {
@@ -52,8 +61,14 @@ func validatePHP(packageName string, paths []string) ValidationResult {
 		}
 	}
 
+	result.Metadata.Created = oldest
+	result.Metadata.LatestVersion = versions[0].Version
+	if t, err := time.Parse(time.RFC3339, versions[0].Time); err == nil {
+		result.Metadata.LatestRelease = t
+	}
+
 	age := time.Since(oldest)
-	if age < 30*24*time.Hour {
+	if age < NewPackageAge {
 		result.Status = "investigate"
 		result.Details = fmt.Sprintf("Very new package (published %s ago)", age.Round(time.Hour*24))
 	} else {
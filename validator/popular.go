@@ -0,0 +1,104 @@
+package validator
+
+// popularPackages are some of the most depended-on names in each registry.
+// Squatters register near misses of exactly these, so a name one or two
+// edits away from one of them is a typosquat signal.
+var popularPackages = map[string][]string{
+	"npm": {
+		"axios", "babel-core", "body-parser", "chalk", "cheerio", "classnames", "commander",
+		"cors", "cross-env", "debug", "dotenv", "electron", "eslint", "express", "fs-extra",
+		"glob", "graphql", "jest", "jquery", "js-yaml", "jsonwebtoken", "left-pad", "lodash",
+		"minimist", "mocha", "moment", "mongoose", "mysql", "next", "node-fetch", "nodemon",
+		"prettier", "prop-types", "react", "react-dom", "react-router", "redux", "request",
+		"rimraf", "rxjs", "semver", "socket.io", "styled-components", "tslib", "typescript",
+		"underscore", "uuid", "vue", "webpack", "ws", "yargs",
+	},
+	"pypi": {
+		"aiohttp", "beautifulsoup4", "boto3", "botocore", "certifi", "charset-normalizer",
+		"click", "colorama", "cryptography", "django", "fastapi", "flask", "idna", "jinja2",
+		"matplotlib", "numpy", "opencv-python", "pandas", "pillow", "pip", "psycopg2",
+		"pydantic", "pyjwt", "pytest", "python-dateutil", "pytz", "pyyaml", "requests",
+		"scikit-learn", "scipy", "selenium", "setuptools", "six", "sqlalchemy", "tensorflow",
+		"torch", "tqdm", "typing-extensions", "urllib3", "wheel",
+	},
+	"go": {
+		"github.com/gin-gonic/gin", "github.com/go-sql-driver/mysql", "github.com/golang/protobuf",
+		"github.com/google/uuid", "github.com/gorilla/mux", "github.com/gorilla/websocket",
+		"github.com/labstack/echo/v4", "github.com/pkg/errors", "github.com/prometheus/client_golang",
+		"github.com/sirupsen/logrus", "github.com/spf13/cobra", "github.com/spf13/viper",
+		"github.com/stretchr/testify", "go.uber.org/zap", "google.golang.org/grpc",
+		"gopkg.in/yaml.v3", "gorm.io/gorm",
+	},
+	"php": {
+		"doctrine/orm", "guzzlehttp/guzzle", "laravel/framework", "monolog/monolog",
+		"phpunit/phpunit", "symfony/console", "symfony/http-foundation", "vlucas/phpdotenv",
+	},
+	"ruby": {
+		"activerecord", "activesupport", "bundler", "devise", "faker", "json", "nokogiri",
+		"puma", "rack", "rails", "rake", "rspec", "rubocop", "sidekiq", "sinatra",
+	},
+	"actions": {
+		"actions/cache", "actions/checkout", "actions/download-artifact", "actions/github-script",
+		"actions/setup-go", "actions/setup-java", "actions/setup-node", "actions/setup-python",
+		"actions/upload-artifact", "aws-actions/configure-aws-credentials", "azure/login",
+		"codecov/codecov-action", "docker/build-push-action", "docker/login-action",
+		"docker/metadata-action", "docker/setup-buildx-action", "docker/setup-qemu-action",
+		"github/codeql-action", "golangci/golangci-lint-action", "google-github-actions/auth",
+		"goreleaser/goreleaser-action", "hashicorp/setup-terraform", "peaceiris/actions-gh-pages",
+		"peter-evans/create-pull-request", "pnpm/action-setup", "softprops/action-gh-release",
+	},
+	"rust": {
+		"anyhow", "bytes", "chrono", "clap", "futures", "hyper", "lazy_static", "libc", "log",
+		"rand", "regex", "reqwest", "serde", "serde_json", "syn", "thiserror", "tokio", "tracing",
+	},
+}
+
+// Names this long or longer count as typosquats when one edit, or two
+// edits, away from a popular package; shorter names collide by chance
+const (
+	OneEditMinLength = 4
+	TwoEditMinLength = 8
+)
+
+// nearestPopular returns the closest popular name in the ecosystem and its
+// edit distance, or "" when the package is itself one of them
+func nearestPopular(source, name string) (string, int) {
+	best, bestDist := "", -1
+	for _, popular := range popularPackages[source] {
+		if popular == name {
+			return "", 0
+		}
+		if d := editDistance(name, popular); bestDist == -1 || d < bestDist {
+			best, bestDist = popular, d
+		}
+	}
+	return best, bestDist
+}
+
+// editDistance is the optimal string alignment distance, so a swapped pair
+// of letters ("reqeusts") counts as a single edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
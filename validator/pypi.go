@@ -3,17 +3,26 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Kelcode-Dev/vibe-validator/utils"
 )
 
+// PyPIIndex is the index PyPI packages are looked up in, through its JSON
+// API at /pypi/<name>/json
+var PyPIIndex = "https://pypi.org"
+
 type pypiMetadata struct {
 	Info struct {
-		ProjectURL string `json:"project_url"`
-		HomePage   string `json:"home_page"`
-		PackageURL string `json:"package_url"`
+		ProjectURL  string            `json:"project_url"`
+		HomePage    string            `json:"home_page"`
+		PackageURL  string            `json:"package_url"`
+		ProjectURLs map[string]string `json:"project_urls"`
+		License     string            `json:"license"`
+		LicenseExpr string            `json:"license_expression"`
+		Classifiers []string          `json:"classifiers"`
+		Version     string            `json:"version"`
 	} `json:"info"`
 	Releases map[string][]struct {
 		UploadTimeISO string `json:"upload_time_iso_8601"`
@@ -23,14 +32,18 @@ type pypiMetadata struct {
 func validatePyPI(packageName string, paths []string) ValidationResult {
 	result := ValidationResult{Name: packageName, Source: "pypi", Paths: paths}
 
-	url := fmt.Sprintf("https://pypi.org/pypi/%s/json", packageName)
-	resp, err := http.Get(url)
-	if err != nil || resp == nil || resp.StatusCode != 200 {
+	url := fmt.Sprintf("%s/pypi/%s/json", strings.TrimSuffix(PyPIIndex, "/"), packageName)
+	resp, err := registryGet(url)
+	if err != nil {
+		return unreachable(result, "PyPI", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
 		result.Status = "not_found"
 		result.Details = "Not found on PyPI"
 		return result
 	}
-	defer resp.Body.Close()
 
 	var data pypiMetadata
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -39,6 +52,9 @@ func validatePyPI(packageName string, paths []string) ValidationResult {
 		return result
 	}
 
+	result.Repository = data.repositoryURL()
+	result.License = data.licenseName()
+
 	var oldest time.Time
 	for _, versions := range data.Releases {
 		for _, release := range versions {
@@ -49,8 +65,16 @@ func validatePyPI(packageName string, paths []string) ValidationResult {
 		}
 	}
 
+	result.Metadata.Created = oldest
+	result.Metadata.LatestVersion = data.Info.Version
+	for _, release := range data.Releases[data.Info.Version] {
+		if t, err := time.Parse(time.RFC3339, release.UploadTimeISO); err == nil && t.After(result.Metadata.LatestRelease) {
+			result.Metadata.LatestRelease = t
+		}
+	}
+
 	age := time.Since(oldest)
-	if age < 30*24*time.Hour {
+	if age < NewPackageAge {
 		result.Status = "investigate"
 		result.Details = fmt.Sprintf("Very new package (published %s ago)", utils.HumanDuration(age))
 	} else {
@@ -60,3 +84,47 @@ func validatePyPI(packageName string, paths []string) ValidationResult {
 
 	return result
 }
+
+// repositoryURL picks the source link out of project_urls, whose labels are
+// free text, falling back to any home page that points at a forge
+func (m pypiMetadata) repositoryURL() string {
+	for _, label := range []string{"Source", "Source Code", "Source code", "Repository", "Code", "GitHub", "Github"} {
+		if url := m.Info.ProjectURLs[label]; url != "" {
+			return url
+		}
+	}
+	for _, url := range m.Info.ProjectURLs {
+		if isForgeURL(url) {
+			return url
+		}
+	}
+	if isForgeURL(m.Info.HomePage) {
+		return m.Info.HomePage
+	}
+	return ""
+}
+
+// licenseName prefers the PEP 639 license expression, then the free text
+// license field (unless someone pasted the whole license text into it),
+// then the trove classifiers
+func (m pypiMetadata) licenseName() string {
+	if m.Info.LicenseExpr != "" {
+		return m.Info.LicenseExpr
+	}
+	if license := strings.TrimSpace(m.Info.License); license != "" && len(license) < 100 && !strings.Contains(license, "\n") {
+		return license
+	}
+
+	var names []string
+	for _, c := range m.Info.Classifiers {
+		if strings.HasPrefix(c, "License :: ") {
+			parts := strings.Split(c, " :: ")
+			name := parts[len(parts)-1]
+			if id, ok := lookupLicense(name); ok {
+				name = id
+			}
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, " OR ")
+}
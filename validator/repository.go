@@ -0,0 +1,246 @@
+package validator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+)
+
+// repositoryEcosystems are the registries whose metadata carries a repository link
+var repositoryEcosystems = map[string]bool{
+	"npm":  true,
+	"pypi": true,
+	"rust": true,
+	"ruby": true,
+}
+
+// publicForges are hosts trusted to be a repository when a registry only
+// gives us a home page
+var publicForges = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"codeberg.org":  true,
+}
+
+// CheckRepositories verifies the repository each package links to, flagging
+// links that are missing, dead or don't look like the package, and links
+// claimed by several unrelated packages (star-jacking)
+func CheckRepositories(results []ValidationResult, cfg config.Repository) {
+	exists := map[string]bool{}
+	claims := map[string][]int{}
+
+	for i := range results {
+		r := &results[i]
+		if r.Status == "not_found" || !repositoryEcosystems[r.Source] {
+			continue
+		}
+
+		if r.Repository == "" {
+			r.addFinding(Finding{Type: "repo_missing", Details: "No source repository linked"})
+			continue
+		}
+
+		host, slug, ok := splitRepoURL(r.Repository)
+		if !ok {
+			r.addFinding(Finding{Type: "repo_invalid", Details: fmt.Sprintf("Unrecognised repository link %s", r.Repository)})
+			continue
+		}
+		key := strings.ToLower(host + "/" + slug)
+
+		if forge, ok := forgeFor(host, cfg.Forges); ok {
+			found, checked := exists[key]
+			if !checked {
+				var err error
+				if found, err = repoExists(forge, slug); err == nil {
+					exists[key] = found
+				} else {
+					// rate limits and outages say nothing about the repository
+					found = true
+				}
+			}
+			if !found {
+				r.addFinding(Finding{Type: "repo_not_found", Details: fmt.Sprintf("Repository %s does not exist", key)})
+				continue
+			}
+		}
+
+		if !nameMatchesRepo(r.Name, slug) {
+			r.addFinding(Finding{Type: "repo_mismatch", Details: fmt.Sprintf("Repository %s does not match package name", slug)})
+		}
+		claims[key] = append(claims[key], i)
+	}
+
+	for key, idx := range claims {
+		_, slug, _ := strings.Cut(key, "/")
+		families := map[string]bool{}
+		var unrelated []int
+		for _, i := range idx {
+			if !nameMatchesRepo(results[i].Name, slug) {
+				unrelated = append(unrelated, i)
+				families[packageFamily(results[i].Name)] = true
+			}
+		}
+		if len(families) < cfg.SharedThreshold {
+			continue
+		}
+		for _, i := range unrelated {
+			results[i].addFinding(Finding{Type: "repo_shared", Details: fmt.Sprintf("Repository %s is claimed by %d unrelated packages", key, len(families))})
+		}
+	}
+}
+
+// splitRepoURL reduces the many ways registries spell a repository link
+// (git+https, scp-style ssh, npm shorthands) to a host and owner/repo slug
+func splitRepoURL(raw string) (host, slug string, ok bool) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "git+")
+
+	for prefix, h := range map[string]string{"github:": "github.com", "gitlab:": "gitlab.com", "bitbucket:": "bitbucket.org"} {
+		if strings.HasPrefix(raw, prefix) {
+			raw = "https://" + h + "/" + strings.TrimPrefix(raw, prefix)
+		}
+	}
+	if at := strings.Index(raw, "@"); at != -1 && !strings.Contains(raw, "://") {
+		// git@github.com:owner/repo.git
+		raw = "ssh://" + strings.Replace(raw[at+1:], ":", "/", 1)
+	} else if !strings.Contains(raw, "://") && strings.Count(raw, "/") == 1 && !strings.Contains(strings.Split(raw, "/")[0], ".") {
+		// npm allows a bare owner/repo for GitHub
+		raw = "https://github.com/" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", "", false
+	}
+	host = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	var parts []string
+	for _, p := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if p == "-" || p == "tree" || p == "blob" {
+			break
+		}
+		parts = append(parts, p)
+	}
+	// only GitLab nests projects in subgroups
+	if !strings.Contains(host, "gitlab") && len(parts) > 2 {
+		parts = parts[:2]
+	}
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	parts[len(parts)-1] = strings.TrimSuffix(parts[len(parts)-1], ".git")
+
+	return host, strings.Join(parts, "/"), true
+}
+
+// isForgeURL reports whether a link points at a repository on a public forge
+func isForgeURL(raw string) bool {
+	host, _, ok := splitRepoURL(raw)
+	return ok && publicForges[host]
+}
+
+func forgeFor(host string, forges []config.Forge) (config.Forge, bool) {
+	for _, f := range forges {
+		if strings.EqualFold(f.Host, host) {
+			return f, true
+		}
+	}
+	return config.Forge{}, false
+}
+
+// repoExists asks the forge API whether the repository is there; errors
+// mean the forge couldn't tell us, not that the repository is missing
+func repoExists(forge config.Forge, slug string) (bool, error) {
+	api := strings.TrimSuffix(forge.API, "/")
+
+	var endpoint string
+	switch forge.Kind {
+	case "github", "gitea":
+		endpoint = fmt.Sprintf("%s/repos/%s", api, slug)
+	case "gitlab":
+		endpoint = fmt.Sprintf("%s/projects/%s", api, url.PathEscape(slug))
+	default:
+		return false, fmt.Errorf("unknown forge kind %q", forge.Kind)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	if token := os.Getenv(forge.TokenEnv); forge.TokenEnv != "" && token != "" {
+		switch forge.Kind {
+		case "github":
+			req.Header.Set("Authorization", "Bearer "+token)
+		case "gitlab":
+			req.Header.Set("PRIVATE-TOKEN", token)
+		case "gitea":
+			req.Header.Set("Authorization", "token "+token)
+		}
+	}
+
+	resp, err := registryClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+		return true, nil
+	case 404:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s returned %s", forge.Host, resp.Status)
+	}
+}
+
+// nameMatchesRepo is deliberately loose: monorepos and prefixes such as
+// python-foo or foo-rs are fine, a package pointing at an unrelated
+// project is not
+func nameMatchesRepo(name, slug string) bool {
+	target := squash(slug)
+	full := squash(name)
+	if full == "" || strings.Contains(target, full) {
+		return true
+	}
+
+	parts := strings.Split(slug, "/")
+	if repo := squash(parts[len(parts)-1]); len(repo) >= 3 && strings.Contains(full, repo) {
+		return true
+	}
+
+	for _, word := range nameWords(name) {
+		word = strings.TrimSuffix(word, "s")
+		if len(word) >= 3 && strings.Contains(target, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// packageFamily groups packages that legitimately share a repository, such
+// as everything under one npm scope
+func packageFamily(name string) string {
+	if strings.HasPrefix(name, "@") {
+		if scope, _, found := strings.Cut(name, "/"); found {
+			return scope
+		}
+	}
+	return squash(name)
+}
+
+func squash(s string) string {
+	return strings.Join(nameWords(s), "")
+}
+
+func nameWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
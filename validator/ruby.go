@@ -3,12 +3,18 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 )
 
 type rubyGemsResponse struct {
-	CreatedAt string `json:"created_at"` // ISO8601
+	CreatedAt     string   `json:"created_at"` // ISO8601
+	SourceCodeURI string   `json:"source_code_uri"`
+	HomepageURI   string   `json:"homepage_uri"`
+	Licenses      []string `json:"licenses"`
+	Version       string   `json:"version"`
+	VersionAt     string   `json:"version_created_at"`
+	Downloads     int64    `json:"downloads"`
 }
 
 func validateRuby(gemName string, paths []string) ValidationResult {
@@ -19,13 +25,17 @@ func validateRuby(gemName string, paths []string) ValidationResult {
 	}
 
 	url := fmt.Sprintf("https://rubygems.org/api/v1/gems/%s.json", gemName)
-	resp, err := http.Get(url)
-	if err != nil || resp == nil || resp.StatusCode != 200 {
+	resp, err := registryGet(url)
+	if err != nil {
+		return unreachable(result, "RubyGems", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
 		result.Status = "not_found"
 		result.Details = "Not found on RubyGems"
 		return result
 	}
-	defer resp.Body.Close()
 
 	var data rubyGemsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -34,6 +44,20 @@ func validateRuby(gemName string, paths []string) ValidationResult {
 		return result
 	}
 
+	result.Repository = data.SourceCodeURI
+	if result.Repository == "" && isForgeURL(data.HomepageURI) {
+		result.Repository = data.HomepageURI
+	}
+
+	// a gem listing several licenses may be used under any of them
+	result.License = strings.Join(data.Licenses, " OR ")
+
+	result.Metadata.LatestVersion = data.Version
+	if t, err := time.Parse(time.RFC3339, data.VersionAt); err == nil {
+		result.Metadata.LatestRelease = t
+	}
+	result.Metadata.Downloads, result.Metadata.DownloadPeriod = data.Downloads, "total"
+
 	t, err := time.Parse(time.RFC3339, data.CreatedAt)
 	if err != nil {
 		result.Status = "investigate"
@@ -41,8 +65,10 @@ func validateRuby(gemName string, paths []string) ValidationResult {
 		return result
 	}
 
+	result.Metadata.Created = t
+
 	age := time.Since(t)
-	if age < 30*24*time.Hour {
+	if age < NewPackageAge {
 		result.Status = "investigate"
 		result.Details = fmt.Sprintf("Very new package (published %s ago)", age.Round(time.Hour*24))
 	} else {
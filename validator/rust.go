@@ -3,14 +3,20 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 )
 
 type cratesResponse struct {
 	Crate struct {
-		CreatedAt string `json:"created_at"` // ISO8601
+		CreatedAt  string `json:"created_at"` // ISO8601
+		Repository string `json:"repository"`
+		Downloads  int64  `json:"downloads"`
 	} `json:"crate"`
+	Versions []struct {
+		Num       string `json:"num"`
+		CreatedAt string `json:"created_at"`
+		License   string `json:"license"` // SPDX expression, newest version first
+	} `json:"versions"`
 }
 
 func validateRust(crateName string, paths []string) ValidationResult {
@@ -21,13 +27,17 @@ func validateRust(crateName string, paths []string) ValidationResult {
 	}
 
 	url := fmt.Sprintf("https://crates.io/api/v1/crates/%s", crateName)
-	resp, err := http.Get(url)
-	if err != nil || resp == nil || resp.StatusCode != 200 {
+	resp, err := registryGet(url)
+	if err != nil {
+		return unreachable(result, "crates.io", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
 		result.Status = "not_found"
 		result.Details = "Not found on crates.io"
 		return result
 	}
-	defer resp.Body.Close()
 
 	var data cratesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -36,6 +46,16 @@ func validateRust(crateName string, paths []string) ValidationResult {
 		return result
 	}
 
+	result.Repository = data.Crate.Repository
+	result.Metadata.Downloads, result.Metadata.DownloadPeriod = data.Crate.Downloads, "total"
+	if len(data.Versions) > 0 {
+		result.License = data.Versions[0].License
+		result.Metadata.LatestVersion = data.Versions[0].Num
+		if t, err := time.Parse(time.RFC3339, data.Versions[0].CreatedAt); err == nil {
+			result.Metadata.LatestRelease = t
+		}
+	}
+
 	t, err := time.Parse(time.RFC3339, data.Crate.CreatedAt)
 	if err != nil {
 		result.Status = "investigate"
@@ -43,8 +63,10 @@ func validateRust(crateName string, paths []string) ValidationResult {
 		return result
 	}
 
+	result.Metadata.Created = t
+
 	age := time.Since(t)
-	if age < 30*24*time.Hour {
+	if age < NewPackageAge {
 		result.Status = "investigate"
 		result.Details = fmt.Sprintf("Very new package (published %s ago)", age.Round(time.Hour*24))
 	} else {
@@ -0,0 +1,182 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Kelcode-Dev/vibe-validator/config"
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+	"github.com/Kelcode-Dev/vibe-validator/utils"
+)
+
+// Signal is one input to the risk score and what it contributed
+type Signal struct {
+	Name   string  `json:"name"`
+	Value  string  `json:"value"`  // what was observed, e.g. "published 3 days ago"
+	Risk   float64 `json:"risk"`   // 0 (fine) to 1 (as bad as it gets)
+	Weight float64 `json:"weight"` // points the signal is worth at full risk
+	Points float64 `json:"points"` // Risk * Weight
+}
+
+// NewPackageAge is how recently a package can have been first published
+// before the registry checks flag it as new
+const NewPackageAge = 30 * 24 * time.Hour
+
+// popularityFloor is the download count at which a package stops looking
+// obscure, per counting period
+var popularityFloor = map[string]float64{
+	"week":  10000,
+	"total": 100000,
+}
+
+// ScoreResults attaches a 0-100 risk score and its breakdown to every
+// result. Signals only count when there is data for them, and the points
+// are summed and capped at 100 so one strong signal is enough to stand out.
+func ScoreResults(results []ValidationResult, cfg config.Score) {
+	now := time.Now()
+
+	for i := range results {
+		r := &results[i]
+		r.Signals = nil
+
+		add := func(name, value string, risk float64) {
+			weight := cfg.Weights[name]
+			r.Signals = append(r.Signals, Signal{
+				Name:   name,
+				Value:  value,
+				Risk:   risk,
+				Weight: weight,
+				Points: math.Round(risk*weight*10) / 10,
+			})
+		}
+
+		if r.Status == "not_found" {
+			add("existence", r.Details, 1)
+		} else {
+			add("existence", "found in registry", 0)
+		}
+
+		meta := r.Metadata
+		if !meta.Created.IsZero() {
+			age := now.Sub(meta.Created)
+			add("package_age", "first published "+utils.HumanDuration(age), ageRisk(age, NewPackageAge.Hours()/24, 90, 365))
+		}
+		if !meta.LatestRelease.IsZero() {
+			age := now.Sub(meta.LatestRelease)
+			add("version_age", fmt.Sprintf("%s released %s", meta.LatestVersion, utils.HumanDuration(age)), ageRisk(age, 7, 30, 0))
+		}
+
+		if floor, ok := popularityFloor[meta.DownloadPeriod]; ok {
+			risk := 1 - math.Log10(float64(meta.Downloads)+1)/math.Log10(floor)
+			add("popularity", fmt.Sprintf("%d downloads (%s)", meta.Downloads, meta.DownloadPeriod), clamp(risk))
+		}
+
+		if len(meta.InstallScripts) > 0 {
+			add("install_scripts", "runs "+strings.Join(meta.InstallScripts, ", "), 1)
+		}
+		if meta.NewPublisher != "" {
+			add("maintainer_change", fmt.Sprintf("%s published by first-time publisher %s", meta.LatestVersion, meta.NewPublisher), 1)
+		}
+
+		name := osv.NormalizeName(r.Source, r.Name)
+		if popular, dist := nearestPopular(r.Source, name); popular != "" {
+			switch {
+			case dist == 1 && len(name) >= OneEditMinLength:
+				add("typosquat", fmt.Sprintf("one edit from %s", popular), 1)
+			case dist == 2 && len(name) >= TwoEditMinLength:
+				add("typosquat", fmt.Sprintf("two edits from %s", popular), 0.5)
+			}
+		}
+
+		if risk, value := repositoryRisk(r.Findings); value != "" {
+			add("repository", value, risk)
+		}
+		if risk, value := vulnerabilityRisk(r.Findings); value != "" {
+			add("vulnerabilities", value, risk)
+		}
+
+		total := 0.0
+		for _, s := range r.Signals {
+			total += s.Points
+		}
+		if r.Status == "malicious" {
+			total = 100 // nothing else matters
+		}
+		r.Score = math.Min(100, math.Round(total))
+
+		if r.Status == "safe" && r.Score >= cfg.Investigate {
+			r.Status = "investigate"
+		}
+
+		sort.SliceStable(r.Signals, func(a, b int) bool {
+			return r.Signals[a].Points > r.Signals[b].Points
+		})
+	}
+}
+
+// ageRisk is 1 below the first threshold (in days), easing off to 0 past
+// the last; a zero threshold ends the scale early
+func ageRisk(age time.Duration, days ...float64) float64 {
+	d := age.Hours() / 24
+	risks := []float64{1, 0.5, 0.2}
+	for i, limit := range days {
+		if limit == 0 {
+			break
+		}
+		if d < limit {
+			return risks[i]
+		}
+	}
+	return 0
+}
+
+func repositoryRisk(findings []Finding) (float64, string) {
+	risk := map[string]float64{
+		"repo_not_found": 1,
+		"repo_shared":    1,
+		"repo_mismatch":  0.6,
+		"repo_invalid":   0.5,
+		"repo_missing":   0.3,
+		"ref_not_found":  1,
+		"ref_unpinned":   0.2,
+	}
+
+	worst, value := 0.0, ""
+	for _, f := range findings {
+		if r, ok := risk[f.Type]; ok && r > worst {
+			worst, value = r, f.Details
+		}
+	}
+	return worst, value
+}
+
+func vulnerabilityRisk(findings []Finding) (float64, string) {
+	risk := map[string]float64{"CRITICAL": 1, "HIGH": 0.75, "MEDIUM": 0.5, "LOW": 0.25}
+
+	worst, count := 0.0, 0
+	for _, f := range findings {
+		if f.Type != "vulnerability" {
+			continue
+		}
+		count++
+		r, ok := risk[f.Severity]
+		if !ok {
+			r = 0.5 // unscored advisories are taken as medium
+		}
+		worst = math.Max(worst, r)
+	}
+	if count == 0 {
+		return 0, ""
+	}
+	if count == 1 {
+		return worst, "1 known vulnerability"
+	}
+	return worst, fmt.Sprintf("%d known vulnerabilities", count)
+}
+
+func clamp(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
@@ -0,0 +1,14 @@
+package validator
+
+// validateRemoteScript reports a download run straight in a shell. There's
+// no registry to ask, and whatever is served at the URL can change at any
+// time, so it always needs a look.
+func validateRemoteScript(source string, paths []string) ValidationResult {
+	return ValidationResult{
+		Name:    source,
+		Source:  "shell",
+		Paths:   paths,
+		Status:  "investigate",
+		Details: "Downloaded script piped into a shell",
+	}
+}
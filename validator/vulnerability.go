@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Kelcode-Dev/vibe-validator/osv"
+)
+
+// CheckVulnerabilities flags every pinned version that an OSV advisory
+// covers. Dependencies without a pinned version are skipped as there is no
+// telling which advisories apply, and MAL- entries are left to
+// CheckMalicious.
+func CheckVulnerabilities(results []ValidationResult, db *osv.DB) {
+	for i := range results {
+		r := &results[i]
+		for _, version := range r.Versions {
+			for _, adv := range db.Query(r.Source, r.Name, version) {
+				if adv.Malicious() {
+					continue
+				}
+				r.addFinding(Finding{
+					Type:      "vulnerability",
+					Details:   advisoryDetails(adv, version),
+					Severity:  adv.Severity,
+					Reference: adv.ID,
+				})
+			}
+		}
+	}
+}
+
+// advisoryDetails reads like "GHSA-j8r2-6x86-q33q (CVE-2023-32681, MEDIUM) in 2.28.1, fixed in 2.31.0"
+func advisoryDetails(adv osv.Advisory, version string) string {
+	var tags []string
+	for _, alias := range adv.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			tags = append(tags, alias)
+			break
+		}
+	}
+	if adv.Severity != "" {
+		tags = append(tags, adv.Severity)
+	}
+
+	details := adv.ID
+	if len(tags) > 0 {
+		details += fmt.Sprintf(" (%s)", strings.Join(tags, ", "))
+	}
+	details += " in " + version
+	if len(adv.Fixed) > 0 {
+		details += ", fixed in " + strings.Join(adv.Fixed, ", ")
+	} else {
+		details += ", no fix available"
+	}
+	return details
+}